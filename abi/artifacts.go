@@ -0,0 +1,213 @@
+package abi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Artifact is a compiled contract, as found in Foundry (out/*.sol/*.json),
+// Hardhat (artifacts/**/*.json) and Truffle (build/contracts/*.json) build directories
+type Artifact struct {
+	// Path is the location of the artifact file
+	Path         string
+	ContractName string
+	SourceName   string
+	ABI          eth_abi.ABI
+
+	// Bytecode and DeployedBytecode are nil when missing; unlinked library placeholders, e.g. __$…$__, are replaced
+	// with zero addresses so that the length is that of the linked bytecode
+	Bytecode         []byte
+	DeployedBytecode []byte
+}
+
+type artifactJSON struct {
+	ContractName     string          `json:"contractName"`
+	SourceName       string          `json:"sourceName"`
+	SourcePath       string          `json:"sourcePath"`
+	ABI              json.RawMessage `json:"abi"`
+	Bytecode         json.RawMessage `json:"bytecode"`
+	DeployedBytecode json.RawMessage `json:"deployedBytecode"`
+	Metadata         json.RawMessage `json:"metadata"`
+}
+
+// LoadArtifactsDir loads all the artifacts found under a directory
+func LoadArtifactsDir(dir string) ([]*Artifact, error) {
+	return LoadArtifacts(os.DirFS(dir))
+}
+
+// LoadArtifacts walks a file system and loads all the JSON files holding an "abi" key;
+// other JSON files, such as Hardhat debug files and build info, are skipped
+func LoadArtifacts(fsys fs.FS) ([]*Artifact, error) {
+	var res []*Artifact
+
+	var err = fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || path.Ext(p) != ".json" || strings.HasSuffix(p, ".dbg.json") {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, p)
+
+		if err != nil {
+			return err
+		}
+
+		artifact, err := ParseArtifact(data)
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+
+		if artifact == nil {
+			return nil
+		}
+
+		artifact.Path = p
+
+		if len(artifact.ContractName) == 0 {
+			// Foundry names artifacts after the contract, e.g. out/Token.sol/Token.json or Token.0.8.19.json
+			artifact.ContractName, _, _ = strings.Cut(path.Base(p), ".")
+		}
+
+		if len(artifact.SourceName) == 0 && strings.HasSuffix(path.Dir(p), ".sol") {
+			artifact.SourceName = path.Base(path.Dir(p))
+		}
+
+		res = append(res, artifact)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ParseArtifact parses a Foundry, Hardhat or Truffle artifact; it returns nil if the JSON document is not an object
+// or has no "abi" key, and an error if it is not valid JSON
+func ParseArtifact(data []byte) (*Artifact, error) {
+	var raw artifactJSON
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		var typeErr *json.UnmarshalTypeError
+
+		// not an object, hence not an artifact
+		if errors.As(err, &typeErr) && len(typeErr.Field) == 0 {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if len(raw.ABI) == 0 || string(raw.ABI) == "null" {
+		return nil, nil
+	}
+
+	a, err := JSONABI(raw.ABI)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var res = Artifact{
+		ContractName:     raw.ContractName,
+		SourceName:       raw.SourceName,
+		ABI:              *a,
+		Bytecode:         parseBytecode(raw.Bytecode),
+		DeployedBytecode: parseBytecode(raw.DeployedBytecode),
+	}
+
+	if len(res.SourceName) == 0 {
+		res.SourceName = raw.SourcePath
+	}
+
+	if len(res.SourceName) == 0 {
+		res.SourceName, res.ContractName = parseCompilationTarget(raw.Metadata, res.ContractName)
+	}
+
+	return &res, nil
+}
+
+// parseBytecode handles both plain hex strings (Hardhat, Truffle) and {"object": "0x..."} objects (Foundry)
+func parseBytecode(raw json.RawMessage) []byte {
+	var (
+		s   string
+		obj struct {
+			Object string `json:"object"`
+		}
+	)
+
+	if err := json.Unmarshal(raw, &s); err != nil {
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return nil
+		}
+
+		s = obj.Object
+	}
+
+	if !strings.HasPrefix(s, "0x") {
+		s = "0x" + s
+	}
+
+	b, err := hexutil.Decode(zeroLinkPlaceholders(s))
+
+	if err != nil || len(b) == 0 {
+		return nil
+	}
+
+	return b
+}
+
+// parseCompilationTarget reads the source and contract names from solc metadata, which Foundry embeds as an object
+func parseCompilationTarget(raw json.RawMessage, contractName string) (string, string) {
+	var metadata struct {
+		Settings struct {
+			CompilationTarget map[string]string `json:"compilationTarget"`
+		} `json:"settings"`
+	}
+
+	if err := json.Unmarshal(raw, &metadata); err != nil {
+		return "", contractName
+	}
+
+	for source, name := range metadata.Settings.CompilationTarget {
+		if len(contractName) == 0 {
+			contractName = name
+		}
+
+		return source, contractName
+	}
+
+	return "", contractName
+}
+
+// zeroLinkPlaceholders replaces the library placeholders of a hex string with zero addresses. Placeholders span 40
+// characters, like the addresses they stand for: __$<34 hex chars>$__ since solc 0.5, __<padded library name>__ before.
+func zeroLinkPlaceholders(s string) string {
+	if !strings.Contains(s, "__") {
+		return s
+	}
+
+	var b = []byte(s)
+
+	for i := 2; i+40 <= len(b); i += 2 {
+		if b[i] == '_' && b[i+1] == '_' {
+			copy(b[i:i+40], strings.Repeat("0", 40))
+			i += 38
+		}
+	}
+
+	return string(b)
+}
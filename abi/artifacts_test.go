@@ -0,0 +1,98 @@
+package abi
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+const testABI = `[
+	{"type": "constructor", "inputs": [{"name": "supply", "type": "uint256"}], "stateMutability": "nonpayable"},
+	{"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}], "outputs": [{"name": "", "type": "bool"}], "stateMutability": "nonpayable"},
+	{"type": "function", "name": "price", "inputs": [], "outputs": [{"name": "", "type": "ufixed128x18"}], "stateMutability": "view"},
+	{"type": "event", "name": "Transfer", "inputs": [{"name": "from", "type": "address", "indexed": true}, {"name": "to", "type": "address", "indexed": true}, {"name": "amount", "type": "uint256", "indexed": false}], "anonymous": false},
+	{"type": "error", "name": "InsufficientBalance", "inputs": [{"name": "available", "type": "uint256"}]}
+]`
+
+func TestLoadArtifacts(t *testing.T) {
+	var fsys = fstest.MapFS{
+		"out/Token.sol/Token.json": {Data: []byte(`{
+			"abi": ` + testABI + `,
+			"bytecode": {"object": "0x6080", "linkReferences": {}},
+			"deployedBytecode": {"object": "0x6080"},
+			"metadata": {"settings": {"compilationTarget": {"src/Token.sol": "Token"}}}
+		}`)},
+		"out/build-info/1234.json": {Data: []byte(`{"id": "1234", "output": {}}`)},
+		"artifacts/contracts/Token.sol/Token.json": {Data: []byte(`{
+			"_format": "hh-sol-artifact-1",
+			"contractName": "Token",
+			"sourceName": "contracts/Token.sol",
+			"abi": ` + testABI + `,
+			"bytecode": "0x608073__$0123456789abcdef0123456789abcdef01$__5b",
+			"deployedBytecode": "0x6080"
+		}`)},
+		"artifacts/contracts/Token.sol/Token.dbg.json": {Data: []byte(`{"_format": "hh-sol-dbg-1"}`)},
+		"build/contracts/Token.json": {Data: []byte(`{
+			"contractName": "Token",
+			"sourcePath": "/project/contracts/Token.sol",
+			"abi": ` + testABI + `,
+			"bytecode": "0x6080",
+			"deployedBytecode": "0x6080"
+		}`)},
+		"README.md":                     {Data: []byte("# artifacts")},
+		"out/cache/solidity-files.json": {Data: []byte(`["src/Token.sol"]`)},
+	}
+
+	artifacts, err := LoadArtifacts(fsys)
+	assert.NoError(t, err)
+	assert.Len(t, artifacts, 3)
+
+	var byPath = lo.KeyBy(artifacts, func(a *Artifact) string { return a.Path })
+
+	for _, test := range []struct {
+		path       string
+		sourceName string
+		bytecode   []byte
+	}{
+		{"out/Token.sol/Token.json", "src/Token.sol", []byte{0x60, 0x80}},
+		{"artifacts/contracts/Token.sol/Token.json", "contracts/Token.sol", append(append([]byte{0x60, 0x80, 0x73}, make([]byte, 20)...), 0x5b)},
+		{"build/contracts/Token.json", "/project/contracts/Token.sol", []byte{0x60, 0x80}},
+	} {
+		t.Run(test.path, func(t *testing.T) {
+			var a = byPath[test.path]
+			assert.NotNil(t, a)
+			assert.Equal(t, "Token", a.ContractName)
+			assert.Equal(t, test.sourceName, a.SourceName)
+			assert.Equal(t, test.bytecode, a.Bytecode)
+			assert.Len(t, a.ABI.Constructor.Inputs, 1)
+			assert.Equal(t, "transfer(address,uint256)", a.ABI.Methods["transfer"].Sig)
+			assert.Equal(t, "ufixed128x18", a.ABI.Methods["price"].Outputs[0].Type.String())
+			assert.Equal(t, "Transfer(address,address,uint256)", a.ABI.Events["Transfer"].Sig)
+			assert.Equal(t, "InsufficientBalance(uint256)", a.ABI.Errors["InsufficientBalance"].Sig)
+		})
+	}
+
+	_, err = LoadArtifacts(fstest.MapFS{"Bad.json": {Data: []byte(`{"abi": [{"type": "function", "name": "f", "inputs": [{"type": "foo"}]}]}`)}})
+	assert.ErrorContains(t, err, "Bad.json")
+
+	_, err = LoadArtifacts(fstest.MapFS{"out/Token.sol/Token.json": {Data: []byte(`{"abi": [{"type": "function", "name": "f"`)}})
+	assert.ErrorContains(t, err, "out/Token.sol/Token.json: unexpected end of JSON input")
+}
+
+func TestParseArtifactLinkPlaceholders(t *testing.T) {
+	for _, bytecode := range []string{
+		"0x608073__$0123456789abcdef0123456789abcdef01$__5b",
+		"608073__SafeMath______________________________5b",
+	} {
+		a, err := ParseArtifact([]byte(`{"abi": [], "bytecode": "` + bytecode + `"}`))
+		assert.NoError(t, err)
+		assert.Equal(t, append(append([]byte{0x60, 0x80, 0x73}, make([]byte, 20)...), 0x5b), a.Bytecode, bytecode)
+	}
+
+	// placeholders of the wrong length cannot stand for an address
+	a, err := ParseArtifact([]byte(`{"abi": [], "bytecode": "0x6080__$1234$__"}`))
+	assert.NoError(t, err)
+	assert.Nil(t, a.Bytecode)
+}
@@ -10,8 +10,8 @@ import (
 type field struct {
 	Type    string
 	Name    string
-	Inputs  []eth_abi.Argument
-	Outputs []eth_abi.Argument
+	Inputs  []eth_abi.ArgumentMarshaling
+	Outputs []eth_abi.ArgumentMarshaling
 
 	// Status indicator which can be: "pure", "view",
 	// "nonpayable" or "payable".
@@ -41,7 +41,13 @@ func JSONEvent(data []byte) (*eth_abi.Event, error) {
 		return nil, fmt.Errorf("wrong field type: %s", field.Type)
 	}
 
-	var evt = eth_abi.NewEvent(field.Name, field.Name, field.Anonymous, field.Inputs)
+	inputs, err := NewArguments(field.Inputs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var evt = eth_abi.NewEvent(field.Name, field.Name, field.Anonymous, inputs)
 	return &evt, nil
 }
 
@@ -60,6 +66,128 @@ func JSONMethod(data []byte) (*eth_abi.Method, error) {
 		return nil, fmt.Errorf("wrong field type: %s", field.Type)
 	}
 
-	var meth = eth_abi.NewMethod(field.Name, field.Name, eth_abi.Function, field.StateMutability, field.Constant, field.Payable, field.Inputs, field.Outputs)
+	inputs, err := NewArguments(field.Inputs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	outputs, err := NewArguments(field.Outputs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var meth = eth_abi.NewMethod(field.Name, field.Name, eth_abi.Function, field.StateMutability, field.Constant, field.Payable, inputs, outputs)
 	return &meth, nil
 }
+
+func JSONError(data []byte) (*eth_abi.Error, error) {
+	var field field
+
+	if err := json.Unmarshal(data, &field); err != nil {
+		return nil, err
+	}
+
+	if len(field.Name) == 0 {
+		return nil, fmt.Errorf("field descriptor name must not be empty")
+	}
+
+	if field.Type != "error" {
+		return nil, fmt.Errorf("wrong field type: %s", field.Type)
+	}
+
+	inputs, err := NewArguments(field.Inputs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var e = eth_abi.NewError(field.Name, inputs)
+	return &e, nil
+}
+
+func JSONConstructor(data []byte) (*eth_abi.Method, error) {
+	var field field
+
+	if err := json.Unmarshal(data, &field); err != nil {
+		return nil, err
+	}
+
+	if field.Type != "constructor" {
+		return nil, fmt.Errorf("wrong field type: %s", field.Type)
+	}
+
+	inputs, err := NewArguments(field.Inputs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var ctor = eth_abi.NewMethod("", "", eth_abi.Constructor, field.StateMutability, field.Constant, field.Payable, inputs, nil)
+	return &ctor, nil
+}
+
+// JSONABI parses a full JSON ABI like eth_abi.JSON, with the types supported by NewType
+func JSONABI(data []byte) (*eth_abi.ABI, error) {
+	var fields []field
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	var res = eth_abi.ABI{
+		Methods: make(map[string]eth_abi.Method),
+		Events:  make(map[string]eth_abi.Event),
+		Errors:  make(map[string]eth_abi.Error),
+	}
+
+	for _, field := range fields {
+		inputs, err := NewArguments(field.Inputs)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", field.Type, field.Name, err)
+		}
+
+		outputs, err := NewArguments(field.Outputs)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", field.Type, field.Name, err)
+		}
+
+		switch field.Type {
+		case "constructor":
+			res.Constructor = eth_abi.NewMethod("", "", eth_abi.Constructor, field.StateMutability, field.Constant, field.Payable, inputs, nil)
+
+		case "function", "":
+			var name = eth_abi.ResolveNameConflict(field.Name, func(s string) bool { _, ok := res.Methods[s]; return ok })
+			res.Methods[name] = eth_abi.NewMethod(name, field.Name, eth_abi.Function, field.StateMutability, field.Constant, field.Payable, inputs, outputs)
+
+		case "fallback":
+			if res.HasFallback() {
+				return nil, fmt.Errorf("only single fallback is allowed")
+			}
+
+			res.Fallback = eth_abi.NewMethod("", "", eth_abi.Fallback, field.StateMutability, field.Constant, field.Payable, nil, nil)
+
+		case "receive":
+			if res.HasReceive() {
+				return nil, fmt.Errorf("only single receive is allowed")
+			}
+
+			res.Receive = eth_abi.NewMethod("", "", eth_abi.Receive, field.StateMutability, field.Constant, field.Payable, nil, nil)
+
+		case "event":
+			var name = eth_abi.ResolveNameConflict(field.Name, func(s string) bool { _, ok := res.Events[s]; return ok })
+			res.Events[name] = eth_abi.NewEvent(name, field.Name, field.Anonymous, inputs)
+
+		case "error":
+			res.Errors[field.Name] = eth_abi.NewError(field.Name, inputs)
+
+		default:
+			return nil, fmt.Errorf("wrong field type: %s", field.Type)
+		}
+	}
+
+	return &res, nil
+}
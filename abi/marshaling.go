@@ -56,7 +56,7 @@ func EventToFieldMarshaling(evt *eth_abi.Event) (*FieldMarshaling, error) {
 
 func MethodToFieldMarshaling(meth *eth_abi.Method) (*FieldMarshaling, error) {
 	var res = FieldMarshaling{
-		Type:            "function",
+		Type:            methodTypeName(meth.Type),
 		Name:            meth.RawName,
 		StateMutability: meth.StateMutability,
 		Constant:        meth.Constant,
@@ -86,6 +86,38 @@ func MethodToFieldMarshaling(meth *eth_abi.Method) (*FieldMarshaling, error) {
 	return &res, nil
 }
 
+func ErrorToFieldMarshaling(e *eth_abi.Error) (*FieldMarshaling, error) {
+	var res = FieldMarshaling{
+		Type: "error",
+		Name: e.Name,
+	}
+
+	for _, input := range e.Inputs {
+		arg, err := ArgumentToArgumentMarshaling(&input)
+
+		if err != nil {
+			return nil, err
+		}
+
+		res.Inputs = append(res.Inputs, arg)
+	}
+
+	return &res, nil
+}
+
+func methodTypeName(t eth_abi.FunctionType) string {
+	switch t {
+	case eth_abi.Constructor:
+		return "constructor"
+	case eth_abi.Fallback:
+		return "fallback"
+	case eth_abi.Receive:
+		return "receive"
+	default:
+		return "function"
+	}
+}
+
 func ArgumentToArgumentMarshaling(arg *eth_abi.Argument) (*ArgumentMarshaling, error) {
 	m, err := TypeToArgumentMarshaling(arg.Type)
 
@@ -101,7 +133,7 @@ func ArgumentToArgumentMarshaling(arg *eth_abi.Argument) (*ArgumentMarshaling, e
 
 func TypeToArgumentMarshaling(t eth_abi.Type) (*ArgumentMarshaling, error) {
 	switch t.T {
-	case eth_abi.IntTy, eth_abi.UintTy, eth_abi.BoolTy, eth_abi.StringTy, eth_abi.AddressTy, eth_abi.FixedBytesTy, eth_abi.BytesTy, eth_abi.HashTy, eth_abi.FunctionTy:
+	case eth_abi.IntTy, eth_abi.UintTy, eth_abi.BoolTy, eth_abi.StringTy, eth_abi.AddressTy, eth_abi.FixedBytesTy, eth_abi.BytesTy, eth_abi.HashTy, eth_abi.FunctionTy, eth_abi.FixedPointTy:
 		return &ArgumentMarshaling{
 			Type:         t.String(),
 			InternalType: t.String(),
@@ -0,0 +1,206 @@
+package abi
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+var fixedPointRegex = regexp.MustCompile(`^(u?)fixed(?:([0-9]+)x([0-9]+))?$`)
+
+// NewType extends eth_abi.NewType with the fixedMxN and ufixedMxN types,
+// which go-ethereum does not support, including inside arrays and tuples.
+func NewType(t string, internalType string, components []eth_abi.ArgumentMarshaling) (eth_abi.Type, error) {
+	if !hasFixedPoint(t, components) {
+		return eth_abi.NewType(t, internalType, components)
+	}
+
+	// fixed point types are built as bytes32, which has the same Go representation, then patched
+	res, err := eth_abi.NewType(fixedPointPlaceholder(t), internalType, fixedPointPlaceholders(components))
+
+	if err != nil {
+		return eth_abi.Type{}, err
+	}
+
+	if err := patchFixedPoint(&res, t, components); err != nil {
+		return eth_abi.Type{}, err
+	}
+
+	return res, nil
+}
+
+func NewArguments(ms []eth_abi.ArgumentMarshaling) (eth_abi.Arguments, error) {
+	var res eth_abi.Arguments
+
+	for _, m := range ms {
+		t, err := NewType(m.Type, m.InternalType, m.Components)
+
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, eth_abi.Argument{
+			Name:    m.Name,
+			Type:    t,
+			Indexed: m.Indexed,
+		})
+	}
+
+	return res, nil
+}
+
+// FixedPointParams returns the signedness, the bit size and the number of decimals of a fixed point type
+func FixedPointParams(t eth_abi.Type) (bool, int, int, error) {
+	if t.T != eth_abi.FixedPointTy {
+		return false, 0, 0, fmt.Errorf("not a fixed point type: %s", t.String())
+	}
+
+	var matches = fixedPointRegex.FindStringSubmatch(t.String())
+
+	if matches == nil || len(matches[2]) == 0 {
+		return false, 0, 0, fmt.Errorf("invalid fixed point type: %s", t.String())
+	}
+
+	var (
+		bits, _     = strconv.Atoi(matches[2])
+		decimals, _ = strconv.Atoi(matches[3])
+	)
+
+	return len(matches[1]) == 0, bits, decimals, nil
+}
+
+func newFixedPointType(t string) (eth_abi.Type, error) {
+	var matches = fixedPointRegex.FindStringSubmatch(t)
+
+	if matches == nil {
+		return eth_abi.Type{}, fmt.Errorf("unsupported arg type: %s", t)
+	}
+
+	// fixed and ufixed are aliases for fixed128x18 and ufixed128x18
+	var bits, decimals = 128, 18
+
+	if len(matches[2]) > 0 {
+		bits, _ = strconv.Atoi(matches[2])
+		decimals, _ = strconv.Atoi(matches[3])
+	}
+
+	if bits < 8 || bits > 256 || bits%8 != 0 || decimals < 1 || decimals > 80 {
+		return eth_abi.Type{}, fmt.Errorf("unsupported arg type: %s", t)
+	}
+
+	var res = eth_abi.Type{
+		T:    eth_abi.FixedPointTy,
+		Size: bits,
+	}
+
+	if err := setStringKind(&res, matches[1]+"fixed"+strconv.Itoa(bits)+"x"+strconv.Itoa(decimals)); err != nil {
+		return eth_abi.Type{}, err
+	}
+
+	return res, nil
+}
+
+func fixedPointPlaceholder(t string) string {
+	var base, suffix = t, ""
+
+	if i := strings.Index(t, "["); i != -1 {
+		base, suffix = t[:i], t[i:]
+	}
+
+	if fixedPointRegex.MatchString(base) {
+		return "bytes32" + suffix
+	}
+
+	return t
+}
+
+func fixedPointPlaceholders(components []eth_abi.ArgumentMarshaling) []eth_abi.ArgumentMarshaling {
+	var res = slices.Clone(components)
+
+	for i := range res {
+		res[i].Type = fixedPointPlaceholder(res[i].Type)
+		res[i].Components = fixedPointPlaceholders(res[i].Components)
+	}
+
+	return res
+}
+
+// patchFixedPoint replaces the bytes32 placeholders of a type built from fixedPointPlaceholder with the fixed point
+// types of t, and updates the canonical names of the enclosing arrays and tuples
+func patchFixedPoint(typ *eth_abi.Type, t string, components []eth_abi.ArgumentMarshaling) error {
+	switch typ.T {
+	case eth_abi.SliceTy, eth_abi.ArrayTy:
+		var i = strings.LastIndex(t, "[")
+
+		if err := patchFixedPoint(typ.Elem, t[:i], components); err != nil {
+			return err
+		}
+
+		return setStringKind(typ, typ.Elem.String()+t[i:])
+
+	case eth_abi.TupleTy:
+		var kinds = make([]string, len(typ.TupleElems))
+
+		for i, elem := range typ.TupleElems {
+			if err := patchFixedPoint(elem, components[i].Type, components[i].Components); err != nil {
+				return err
+			}
+
+			kinds[i] = elem.String()
+		}
+
+		return setStringKind(typ, "("+strings.Join(kinds, ",")+")")
+
+	default:
+		if !fixedPointRegex.MatchString(t) {
+			return nil
+		}
+
+		res, err := newFixedPointType(t)
+
+		if err != nil {
+			return err
+		}
+
+		*typ = res
+		return nil
+	}
+}
+
+func hasFixedPoint(t string, components []eth_abi.ArgumentMarshaling) bool {
+	if i := strings.Index(t, "["); i != -1 {
+		t = t[:i]
+	}
+
+	if fixedPointRegex.MatchString(t) {
+		return true
+	}
+
+	for _, c := range components {
+		if hasFixedPoint(c.Type, c.Components) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setStringKind sets the unexported canonical type name of an eth_abi.Type, which is only ever set by
+// eth_abi.NewType and is used to compute signatures. It is the only place relying on the layout of eth_abi.Type,
+// and fails rather than writing anything if the field is missing or no longer a string; see TestSetStringKind.
+func setStringKind(t *eth_abi.Type, s string) error {
+	var f = reflect.ValueOf(t).Elem().FieldByName("stringKind")
+
+	if !f.IsValid() || f.Kind() != reflect.String {
+		return fmt.Errorf("unsupported go-ethereum version: eth_abi.Type has no stringKind string field")
+	}
+
+	reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem().SetString(s)
+	return nil
+}
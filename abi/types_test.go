@@ -0,0 +1,54 @@
+package abi
+
+import (
+	"reflect"
+	"testing"
+
+	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSetStringKind fails if go-ethereum changes the field setStringKind writes to
+func TestSetStringKind(t *testing.T) {
+	f, ok := reflect.TypeOf(eth_abi.Type{}).FieldByName("stringKind")
+	assert.True(t, ok)
+	assert.Equal(t, reflect.String, f.Type.Kind())
+
+	var typ eth_abi.Type
+	assert.NoError(t, setStringKind(&typ, "fixed128x18"))
+	assert.Equal(t, "fixed128x18", typ.String())
+}
+
+func TestNewType(t *testing.T) {
+	var tests = []struct {
+		t          string
+		components []eth_abi.ArgumentMarshaling
+		str        string
+	}{
+		{"ufixed", nil, "ufixed128x18"},
+		{"fixed64x10[2][]", nil, "fixed64x10[2][]"},
+		{"tuple[2]", []eth_abi.ArgumentMarshaling{
+			{Name: "price", Type: "ufixed128x18"},
+			{Name: "hash", Type: "bytes32"},
+			{Name: "inner", Type: "tuple", Components: []eth_abi.ArgumentMarshaling{{Name: "rates", Type: "fixed8x1[]"}}},
+		}, "(ufixed128x18,bytes32,(fixed8x1[]))[2]"},
+	}
+
+	for _, test := range tests {
+		typ, err := NewType(test.t, "", test.components)
+		assert.NoError(t, err, test.t)
+		assert.Equal(t, test.str, typ.String())
+	}
+
+	typ, err := NewType("tuple", "", []eth_abi.ArgumentMarshaling{{Name: "rate", Type: "fixed168x80"}, {Name: "to", Type: "address"}})
+	assert.NoError(t, err)
+	assert.Equal(t, eth_abi.FixedPointTy, typ.TupleElems[0].T)
+	assert.Equal(t, 168, typ.TupleElems[0].Size)
+	assert.Equal(t, eth_abi.AddressTy, typ.TupleElems[1].T)
+	assert.Equal(t, []string{"rate", "to"}, typ.TupleRawNames)
+
+	for _, bad := range []string{"fixed128x0", "ufixed7x18", "fixed128x81", "fixed264x18"} {
+		_, err := NewType(bad, "", nil)
+		assert.Error(t, err, bad)
+	}
+}
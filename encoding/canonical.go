@@ -0,0 +1,64 @@
+package encoding
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+func checkCanonicalArguments(data []byte, args eth_abi.Arguments, st *decodeState) error {
+	var decodeErr error
+
+	st.raw = true
+
+	res, err := EncodeArguments(func(yield func(*Event, error) bool) {
+		decodeErr = decodeArguments(data, args, yield, st)
+	}, args)
+
+	return checkCanonical(data, res, decodeErr, err)
+}
+
+func checkCanonicalValue(data []byte, t eth_abi.Type, st *decodeState) error {
+	var decodeErr error
+
+	st.raw = true
+
+	res, err := EncodeValue(func(yield func(*Event, error) bool) {
+		decodeErr = decodeValue(data, t, 0, yield, st)
+	}, t)
+
+	if err == nil {
+		var dynamic bool
+
+		// a top-level dynamic value is preceded by its offset, as in a single element tuple
+		if dynamic, err = isDynamic(t); err == nil {
+			res = packElems([][]byte{res}, dynamic)
+		}
+	}
+
+	return checkCanonical(data, res, decodeErr, err)
+}
+
+func checkCanonical(data []byte, res []byte, decodeErr error, encodeErr error) error {
+	if decodeErr != nil && !errors.Is(decodeErr, ErrIterStop) {
+		return decodeErr
+	}
+
+	if encodeErr != nil {
+		return fmt.Errorf("%w: %w", ErrNonCanonicalEncoding, encodeErr)
+	}
+
+	if bytes.Equal(data, res) {
+		return nil
+	}
+
+	var i = 0
+
+	for i < len(data) && i < len(res) && data[i] == res[i] {
+		i++
+	}
+
+	return fmt.Errorf("%w: first difference at byte %d", ErrNonCanonicalEncoding, i)
+}
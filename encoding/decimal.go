@@ -0,0 +1,77 @@
+package encoding
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Decimal is the exact value of a fixed point number: Value * 10^-Decimals
+type Decimal struct {
+	Value    *big.Int
+	Decimals int
+}
+
+func ParseDecimal(s string, decimals int) (Decimal, error) {
+	var (
+		intPart, fracPart, hasFrac = strings.Cut(s, ".")
+		digits                     = strings.TrimLeft(intPart, "+-")
+	)
+
+	if len(digits) == 0 && len(fracPart) == 0 || hasFrac && len(fracPart) == 0 {
+		return Decimal{}, fmt.Errorf("invalid decimal: %s", s)
+	}
+
+	if strings.ContainsAny(fracPart, "+-") {
+		return Decimal{}, fmt.Errorf("invalid decimal: %s", s)
+	}
+
+	var trimmed = strings.TrimRight(fracPart, "0")
+
+	if len(trimmed) > decimals {
+		return Decimal{}, fmt.Errorf("decimal has more than %d decimals: %s", decimals, s)
+	}
+
+	i, ok := new(big.Int).SetString(intPart+trimmed+strings.Repeat("0", decimals-len(trimmed)), 10)
+
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal: %s", s)
+	}
+
+	return Decimal{Value: i, Decimals: decimals}, nil
+}
+
+func (d Decimal) String() string {
+	if d.Value == nil {
+		return "0"
+	}
+
+	var s = new(big.Int).Abs(d.Value).String()
+
+	if d.Decimals > 0 {
+		if len(s) <= d.Decimals {
+			s = strings.Repeat("0", d.Decimals-len(s)+1) + s
+		}
+
+		var (
+			intPart  = s[:len(s)-d.Decimals]
+			fracPart = strings.TrimRight(s[len(s)-d.Decimals:], "0")
+		)
+
+		s = intPart
+
+		if len(fracPart) > 0 {
+			s = s + "." + fracPart
+		}
+	}
+
+	if d.Value.Sign() == -1 {
+		s = "-" + s
+	}
+
+	return s
+}
+
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
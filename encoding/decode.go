@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"unicode/utf8"
 
+	"github.com/agnosticeng/evmabi/abi"
 	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/holiman/uint256"
@@ -15,9 +16,16 @@ func decodeArguments(
 	data []byte,
 	args eth_abi.Arguments,
 	fn YieldFunc,
+	st *decodeState,
 ) error {
 	var virtualArgs = 0
 
+	if err := st.enter(); err != nil {
+		return err
+	}
+
+	defer st.leave()
+
 	if err := Yield(fn, &Event{
 		Type: TupleStart,
 		Len:  len(args),
@@ -34,20 +42,28 @@ func decodeArguments(
 			return err
 		}
 
+		st.pushKey(arg.Name)
+
 		if err := decodeArgument(
 			data,
 			arg,
 			(i+virtualArgs)*32,
 			fn,
+			st,
 		); err != nil {
 			return err
 		}
 
-		if arg.Type.T == eth_abi.ArrayTy && !isDynamic(arg.Type) {
-			virtualArgs += typeSize(arg.Type)/32 - 1
-		} else if arg.Type.T == eth_abi.TupleTy && !isDynamic(arg.Type) {
-			virtualArgs += typeSize(arg.Type)/32 - 1
+		st.pop()
+
+		// static arrays and tuples are encoded in place and span several words, unless hashed in a topic
+		size, err := argumentSize(arg)
+
+		if err != nil {
+			return err
 		}
+
+		virtualArgs += size/32 - 1
 	}
 
 	if err := Yield(fn, &Event{
@@ -64,9 +80,10 @@ func decodeArgument(
 	arg eth_abi.Argument,
 	idx int,
 	fn YieldFunc,
+	st *decodeState,
 ) error {
-	if isDynamic(arg.Type) && arg.Indexed {
-		return ErrDynamicIndexedArgument
+	if arg.Indexed && isHashedTopic(arg.Type) {
+		return decodeTopicHash(data, arg.Type, idx, fn, st)
 	}
 
 	if err := decodeValue(
@@ -74,6 +91,7 @@ func decodeArgument(
 		arg.Type,
 		idx,
 		fn,
+		st,
 	); err != nil {
 		return err
 	}
@@ -81,20 +99,51 @@ func decodeArgument(
 	return nil
 }
 
+// decodeTopicHash decodes an indexed reference type argument, of which only the keccak256 hash is known
+func decodeTopicHash(
+	data []byte,
+	t eth_abi.Type,
+	idx int,
+	fn YieldFunc,
+	st *decodeState,
+) (err error) {
+	defer func() { err = st.wrapError(err, idx, t) }()
+
+	if idx+32 > len(data) {
+		return fmt.Errorf("idx points over data slice boundary")
+	}
+
+	if err := st.countValue(); err != nil {
+		return err
+	}
+
+	return Yield(fn, &Event{
+		Type:    Value,
+		ABIType: t,
+		Value:   TopicHash(common.BytesToHash(data[idx : idx+32])),
+	})
+}
+
 func decodeValue(
 	data []byte,
 	t eth_abi.Type,
 	idx int,
 	fn YieldFunc,
-) error {
+	st *decodeState,
+) (err error) {
+	defer func() { err = st.wrapError(err, idx, t) }()
+
 	if idx+32 > len(data) {
 		return fmt.Errorf("idx points over data slice boundary")
 	}
 
+	if err := st.countValue(); err != nil {
+		return err
+	}
+
 	var (
 		returnOutput  []byte
 		begin, length int
-		err           error
 	)
 
 	if isLengthPrefixed(t) {
@@ -134,6 +183,33 @@ func decodeValue(
 			Value:   i,
 		})
 
+	case eth_abi.FixedPointTy:
+		signed, bits, decimals, err := abi.FixedPointParams(t)
+
+		if err != nil {
+			return err
+		}
+
+		var (
+			i = uint256.NewInt(0).SetBytes(returnOutput)
+			v = i.ToBig()
+		)
+
+		if signed && !fitsInt(i, bits) || !signed && i.BitLen() > bits {
+			return fmt.Errorf("fixed point value does not fit in %d bits", bits)
+		}
+
+		if signed && i.Sign() == -1 {
+			v = uint256.NewInt(0).Neg(i).ToBig()
+			v.Neg(v)
+		}
+
+		return Yield(fn, &Event{
+			Type:    Value,
+			ABIType: t,
+			Value:   Decimal{Value: v, Decimals: decimals},
+		})
+
 	case eth_abi.BoolTy:
 		var b, err = readBool(returnOutput)
 
@@ -162,9 +238,13 @@ func decodeValue(
 		})
 
 	case eth_abi.StringTy:
+		if err := st.countBytes(length); err != nil {
+			return err
+		}
+
 		var v = string(data[begin : begin+length])
 
-		if !utf8.ValidString(v) {
+		if !utf8.ValidString(v) && !st.raw {
 			v = strconv.Quote(v)
 		}
 
@@ -175,6 +255,10 @@ func decodeValue(
 		})
 
 	case eth_abi.BytesTy:
+		if err := st.countBytes(length); err != nil {
+			return err
+		}
+
 		return Yield(fn, &Event{
 			Type:    Value,
 			ABIType: t,
@@ -196,7 +280,13 @@ func decodeValue(
 		})
 
 	case eth_abi.TupleTy:
-		if isDynamic(t) {
+		dynamic, err := isDynamic(t)
+
+		if err != nil {
+			return err
+		}
+
+		if dynamic {
 			offset, overflow := uint256.NewInt(0).SetBytes(data[idx : idx+32]).Uint64WithOverflow()
 
 			if overflow {
@@ -207,39 +297,65 @@ func decodeValue(
 				return fmt.Errorf("offset greater than data length")
 			}
 
-			return decodeTuple(data[offset:], t, fn)
+			return decodeTupleAt(data, int(offset), t, fn, st)
 		}
 
-		return decodeTuple(data[idx:], t, fn)
+		return decodeTupleAt(data, idx, t, fn, st)
 
 	case eth_abi.ArrayTy:
-		if isDynamic(*t.Elem) {
+		dynamic, err := isDynamic(*t.Elem)
+
+		if err != nil {
+			return err
+		}
+
+		if dynamic {
 			var offset = binary.BigEndian.Uint64(returnOutput[len(returnOutput)-8:])
 
 			if offset > uint64(len(data)) {
 				return fmt.Errorf("offset greater than data length")
 			}
 
-			return decodeArray(data[offset:], t, fn, 0, t.Size)
+			return decodeArrayAt(data, int(offset), t, fn, t.Size, st)
 		}
 
-		return decodeArray(data[idx:], t, fn, 0, t.Size)
+		return decodeArrayAt(data, idx, t, fn, t.Size, st)
 
 	case eth_abi.SliceTy:
-		return decodeArray(data[begin:], t, fn, 0, length)
+		return decodeArrayAt(data, begin, t, fn, length, st)
 
 	default:
 		return fmt.Errorf("abi: unknown type %v", t.T)
 	}
 }
 
+func decodeTupleAt(
+	data []byte,
+	offset int,
+	t eth_abi.Type,
+	fn YieldFunc,
+	st *decodeState,
+) error {
+	st.base += offset
+	defer func() { st.base -= offset }()
+
+	return decodeTuple(data[offset:], t, fn, st)
+}
+
 func decodeTuple(
 	data []byte,
 	t eth_abi.Type,
 	fn YieldFunc,
+	st *decodeState,
 ) error {
 	var virtualArgs = 0
 
+	if err := st.enter(); err != nil {
+		return err
+	}
+
+	defer st.leave()
+
 	if err := Yield(fn, &Event{
 		Type:    TupleStart,
 		ABIType: t,
@@ -257,20 +373,27 @@ func decodeTuple(
 			return err
 		}
 
+		st.pushKey(t.TupleRawNames[i])
+
 		if err := decodeValue(
 			data,
 			*elem,
 			(i+virtualArgs)*32,
 			fn,
+			st,
 		); err != nil {
 			return err
 		}
 
-		if elem.T == eth_abi.ArrayTy && !isDynamic(*elem) {
-			virtualArgs += typeSize(*elem)/32 - 1
-		} else if elem.T == eth_abi.TupleTy && !isDynamic(*elem) {
-			virtualArgs += typeSize(*elem)/32 - 1
+		st.pop()
+
+		size, err := typeSize(*elem)
+
+		if err != nil {
+			return err
 		}
+
+		virtualArgs += size/32 - 1
 	}
 
 	if err := Yield(fn, &Event{
@@ -282,22 +405,51 @@ func decodeTuple(
 	return nil
 }
 
+func decodeArrayAt(
+	data []byte,
+	offset int,
+	t eth_abi.Type,
+	fn YieldFunc,
+	size int,
+	st *decodeState,
+) error {
+	st.base += offset
+	defer func() { st.base -= offset }()
+
+	return decodeArray(data[offset:], t, fn, 0, size, st)
+}
+
 func decodeArray(
 	data []byte,
 	t eth_abi.Type,
 	fn YieldFunc,
 	start int,
 	size int,
+	st *decodeState,
 ) error {
 	if size < 0 {
 		return fmt.Errorf("cannot marshal input to array, size is negative (%d)", size)
 	}
 
-	if start+32*size > len(data) {
-		return fmt.Errorf("abi: cannot marshal into go array: offset %d would go over slice boundary (len=%d)", len(data), start+32*size)
+	if start > len(data) || size > (len(data)-start)/32 {
+		return fmt.Errorf("abi: cannot marshal into go array: %d elements would go over slice boundary (len=%d)", size, len(data))
 	}
 
-	var elemSize = typeSize(*t.Elem)
+	if err := st.checkArrayLength(size); err != nil {
+		return err
+	}
+
+	if err := st.enter(); err != nil {
+		return err
+	}
+
+	defer st.leave()
+
+	elemSize, err := typeSize(*t.Elem)
+
+	if err != nil {
+		return err
+	}
 
 	if err := Yield(fn, &Event{
 		Type:    ArrayStart,
@@ -310,14 +462,19 @@ func decodeArray(
 	for i := 0; i < size; i++ {
 		var index = start + (i * elemSize)
 
+		st.pushIndex(i)
+
 		if err := decodeValue(
 			data,
 			*t.Elem,
 			index,
 			fn,
+			st,
 		); err != nil {
 			return err
 		}
+
+		st.pop()
 	}
 
 	if err := Yield(fn, &Event{
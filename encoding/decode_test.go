@@ -0,0 +1,160 @@
+package encoding
+
+import (
+	"testing"
+
+	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func FuzzDecodeArguments(f *testing.F) {
+	var args = newArgs(
+		eth_abi.ArgumentMarshaling{Name: "a", Type: "tuple[]", Components: []eth_abi.ArgumentMarshaling{
+			{Name: "x", Type: "string"},
+			{Name: "y", Type: "function"},
+			{Name: "z", Type: "uint8[2]"},
+		}},
+		eth_abi.ArgumentMarshaling{Name: "b", Type: "bytes[][2]"},
+		eth_abi.ArgumentMarshaling{Name: "c", Type: "int16"},
+	)
+
+	for _, item := range encodeTestData {
+		f.Add(lo.Must(item.Args.Pack(item.Values...)))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for range DecodeArguments(data, args, WithLimits(Limits{MaxValues: 10000})) {
+		}
+	})
+}
+
+func TestDecodeMalformedType(t *testing.T) {
+	var types = []eth_abi.Type{
+		{T: eth_abi.ArrayTy, Size: 2},
+		{T: eth_abi.SliceTy},
+		{T: eth_abi.TupleTy, TupleElems: []*eth_abi.Type{nil}, TupleRawNames: []string{"x"}},
+		{T: 255},
+	}
+
+	for _, typ := range types {
+		var errs []error
+
+		for _, err := range DecodeValue(make([]byte, 64), typ) {
+			errs = append(errs, err)
+		}
+
+		assert.Len(t, errs, 1)
+		assert.Error(t, errs[0])
+	}
+}
+
+func TestDecodeStrict(t *testing.T) {
+	var (
+		int8Ty  = lo.Must(eth_abi.NewType("int8", "", nil))
+		bytesTy = lo.Must(eth_abi.NewType("bytes", "", nil))
+		tests   = []struct {
+			name string
+			t    eth_abi.Type
+			data []byte
+		}{
+			{"positive int not sign-extended", int8Ty, common.LeftPadBytes([]byte{0x80}, 32)},
+			{"negative int not sign-extended", int8Ty, common.LeftPadBytes([]byte{0xff}, 32)},
+			{"non-zero bytes padding", bytesTy, append(append(packUint64(32), packUint64(1)...), common.RightPadBytes([]byte{1, 2}, 32)...)},
+			{"non-canonical offset", bytesTy, append(append(packUint64(64), packUint64(0)...), append(packUint64(1), common.RightPadBytes([]byte{1}, 32)...)...)},
+		}
+	)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, err := range DecodeValue(test.data, test.t) {
+				assert.NoError(t, err)
+			}
+
+			var errs []error
+
+			for _, err := range DecodeValue(test.data, test.t, WithStrict(true)) {
+				errs = append(errs, err)
+			}
+
+			assert.Len(t, errs, 1)
+			assert.ErrorIs(t, errs[0], ErrNonCanonicalEncoding)
+		})
+	}
+}
+
+func TestDecodeIndexedArguments(t *testing.T) {
+	var args = newArgs(
+		eth_abi.ArgumentMarshaling{Name: "name", Type: "string"},
+		eth_abi.ArgumentMarshaling{Name: "pair", Type: "tuple", Components: []eth_abi.ArgumentMarshaling{
+			{Name: "a", Type: "uint256"},
+			{Name: "b", Type: "uint256"},
+		}},
+		eth_abi.ArgumentMarshaling{Name: "owner", Type: "address"},
+	)
+
+	for i := range args {
+		args[i].Indexed = true
+	}
+
+	var (
+		name  = crypto.Keccak256Hash([]byte("vitalik"))
+		pair  = common.HexToHash("0x02")
+		owner = common.HexToAddress("0x03")
+		data  = append(append(name.Bytes(), pair.Bytes()...), common.LeftPadBytes(owner.Bytes(), 32)...)
+	)
+
+	var values []interface{}
+
+	for evt, err := range DecodeArguments(data, args, WithStrict(true)) {
+		assert.NoError(t, err)
+
+		if evt.Type == Value {
+			values = append(values, evt.Value)
+		}
+	}
+
+	assert.Equal(t, []interface{}{TopicHash(name), TopicHash(pair), owner}, values)
+
+	res, err := EncodeArguments(DecodeArguments(data, args), args)
+	assert.NoError(t, err)
+	assert.Equal(t, data, res)
+}
+
+// static tuples are encoded in place, so the arguments following one start after all of its words
+func TestDecodeStaticTuple(t *testing.T) {
+	var args = newArgs(
+		eth_abi.ArgumentMarshaling{Name: "pair", Type: "tuple", Components: []eth_abi.ArgumentMarshaling{
+			{Name: "a", Type: "uint8"},
+			{Name: "b", Type: "address"},
+		}},
+		eth_abi.ArgumentMarshaling{Name: "flag", Type: "bool"},
+		eth_abi.ArgumentMarshaling{Name: "name", Type: "string"},
+	)
+
+	var data = lo.Must(args.Pack(
+		struct {
+			A uint8
+			B common.Address
+		}{7, common.HexToAddress("0x01")},
+		true,
+		"abc",
+	))
+
+	var values []interface{}
+
+	for evt, err := range DecodeArguments(data, args, WithStrict(true)) {
+		assert.NoError(t, err)
+
+		if evt.Type == Value {
+			values = append(values, evt.Value)
+		}
+	}
+
+	assert.Len(t, values, 4)
+	assert.Equal(t, uint64(7), values[0].(*uint256.Int).Uint64())
+	assert.Equal(t, []interface{}{common.HexToAddress("0x01"), true, "abc"}, values[1:])
+}
@@ -0,0 +1,585 @@
+package encoding
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/agnosticeng/evmabi/abi"
+	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+)
+
+type NextFunc func() (*Event, error, bool)
+
+func encodeArguments(
+	next NextFunc,
+	args eth_abi.Arguments,
+) ([]byte, error) {
+	evt, err := pullEvent(next, TupleStart)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if evt.Len != len(args) {
+		return nil, fmt.Errorf("wrong number of arguments; wanted %d but got %d", len(args), evt.Len)
+	}
+
+	var (
+		types  = make([]eth_abi.Type, len(args))
+		topics = make([]bool, len(args))
+	)
+
+	for i, arg := range args {
+		types[i] = arg.Type
+		topics[i] = arg.Indexed && isHashedTopic(arg.Type)
+	}
+
+	res, err := encodeTupleElems(next, types, topics)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := pullEvent(next, TupleEnd); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func encodeValue(
+	next NextFunc,
+	t eth_abi.Type,
+) ([]byte, error) {
+	switch t.T {
+	case eth_abi.TupleTy:
+		evt, err := pullEvent(next, TupleStart)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if evt.Len != len(t.TupleElems) {
+			return nil, fmt.Errorf("wrong number of tuple elements for %s; wanted %d but got %d", t.String(), len(t.TupleElems), evt.Len)
+		}
+
+		var types = make([]eth_abi.Type, len(t.TupleElems))
+
+		for i, elem := range t.TupleElems {
+			types[i] = *elem
+		}
+
+		res, err := encodeTupleElems(next, types, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := pullEvent(next, TupleEnd); err != nil {
+			return nil, err
+		}
+
+		return res, nil
+
+	case eth_abi.ArrayTy, eth_abi.SliceTy:
+		evt, err := pullEvent(next, ArrayStart)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if evt.Len < 0 {
+			return nil, fmt.Errorf("array length is negative (%d)", evt.Len)
+		}
+
+		if t.T == eth_abi.ArrayTy && evt.Len != t.Size {
+			return nil, fmt.Errorf("wrong array length for %s; wanted %d but got %d", t.String(), t.Size, evt.Len)
+		}
+
+		var elems = make([][]byte, evt.Len)
+
+		for i := 0; i < evt.Len; i++ {
+			if elems[i], err = encodeValue(next, *t.Elem); err != nil {
+				return nil, err
+			}
+		}
+
+		if _, err := pullEvent(next, ArrayEnd); err != nil {
+			return nil, err
+		}
+
+		dynamic, err := isDynamic(*t.Elem)
+
+		if err != nil {
+			return nil, err
+		}
+
+		var res = packElems(elems, dynamic)
+
+		if t.T == eth_abi.SliceTy {
+			res = append(packUint64(uint64(evt.Len)), res...)
+		}
+
+		return res, nil
+
+	default:
+		evt, err := pullEvent(next, Value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return encodeScalar(t, evt.Value)
+	}
+}
+
+// encodeTupleElems encodes the elements of a tuple or argument list; elements flagged in topics are indexed
+// reference type arguments, which are encoded in place as their topic hash
+func encodeTupleElems(
+	next NextFunc,
+	types []eth_abi.Type,
+	topics []bool,
+) ([]byte, error) {
+	var elems = make([][]byte, len(types))
+
+	for i, t := range types {
+		evt, err := pullEvent(next, Key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if evt.Index != i {
+			return nil, fmt.Errorf("wrong key index; wanted %d but got %d", i, evt.Index)
+		}
+
+		if topics != nil && topics[i] {
+			topic, err := encodeTopic(next, t)
+
+			if err != nil {
+				return nil, err
+			}
+
+			elems[i] = topic[:]
+			continue
+		}
+
+		if elems[i], err = encodeValue(next, t); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		headSize int
+		res      []byte
+		tail     []byte
+		dynamic  = make([]bool, len(types))
+	)
+
+	for i, t := range types {
+		var err error
+
+		if topics != nil && topics[i] {
+			headSize += 32
+			continue
+		}
+
+		if dynamic[i], err = isDynamic(t); err != nil {
+			return nil, err
+		}
+
+		if dynamic[i] {
+			headSize += 32
+		} else {
+			headSize += len(elems[i])
+		}
+	}
+
+	for i := range types {
+		if dynamic[i] {
+			res = append(res, packUint64(uint64(headSize+len(tail)))...)
+			tail = append(tail, elems[i]...)
+		} else {
+			res = append(res, elems[i]...)
+		}
+	}
+
+	return append(res, tail...), nil
+}
+
+func packElems(elems [][]byte, dynamic bool) []byte {
+	var res, tail []byte
+
+	if !dynamic {
+		for _, elem := range elems {
+			res = append(res, elem...)
+		}
+
+		return res
+	}
+
+	for _, elem := range elems {
+		res = append(res, packUint64(uint64(len(elems)*32+len(tail)))...)
+		tail = append(tail, elem...)
+	}
+
+	return append(res, tail...)
+}
+
+func encodeScalar(t eth_abi.Type, v interface{}) ([]byte, error) {
+	switch t.T {
+	case eth_abi.UintTy:
+		i, err := toUint256(v)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if i.BitLen() > t.Size {
+			return nil, fmt.Errorf("uint needs too many bits (%d/%d)", i.BitLen(), t.Size)
+		}
+
+		var b = i.Bytes32()
+		return b[:], nil
+
+	case eth_abi.IntTy:
+		i, err := toUint256(v)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !fitsInt(i, t.Size) {
+			return nil, fmt.Errorf("int does not fit in %d bits", t.Size)
+		}
+
+		var b = i.Bytes32()
+		return b[:], nil
+
+	case eth_abi.FixedPointTy:
+		signed, bits, decimals, err := abi.FixedPointParams(t)
+
+		if err != nil {
+			return nil, err
+		}
+
+		d, ok := v.(Decimal)
+
+		if !ok || d.Value == nil {
+			return nil, fmt.Errorf("wrong value type for %s: %T", t.String(), v)
+		}
+
+		if d.Decimals != decimals {
+			return nil, fmt.Errorf("wrong number of decimals for %s: %d", t.String(), d.Decimals)
+		}
+
+		if !signed && d.Value.Sign() == -1 {
+			return nil, fmt.Errorf("negative value for %s", t.String())
+		}
+
+		i, err := toUint256(d.Value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if signed && !fitsInt(i, bits) || !signed && i.BitLen() > bits {
+			return nil, fmt.Errorf("fixed point value does not fit in %d bits", bits)
+		}
+
+		var b = i.Bytes32()
+		return b[:], nil
+
+	case eth_abi.BoolTy:
+		b, ok := v.(bool)
+
+		if !ok {
+			return nil, fmt.Errorf("wrong value type for %s: %T", t.String(), v)
+		}
+
+		if b {
+			return packUint64(1), nil
+		}
+
+		return packUint64(0), nil
+
+	case eth_abi.AddressTy:
+		addr, ok := v.(common.Address)
+
+		if !ok {
+			return nil, fmt.Errorf("wrong value type for %s: %T", t.String(), v)
+		}
+
+		return common.LeftPadBytes(addr[:], 32), nil
+
+	case eth_abi.HashTy:
+		hash, ok := v.(common.Hash)
+
+		if !ok {
+			return nil, fmt.Errorf("wrong value type for %s: %T", t.String(), v)
+		}
+
+		return common.CopyBytes(hash[:]), nil
+
+	case eth_abi.StringTy:
+		s, ok := v.(string)
+
+		if !ok {
+			return nil, fmt.Errorf("wrong value type for %s: %T", t.String(), v)
+		}
+
+		return packBytes([]byte(s)), nil
+
+	case eth_abi.BytesTy:
+		b, ok := v.([]byte)
+
+		if !ok {
+			return nil, fmt.Errorf("wrong value type for %s: %T", t.String(), v)
+		}
+
+		return packBytes(b), nil
+
+	case eth_abi.FixedBytesTy, eth_abi.FunctionTy:
+		b, ok := v.([]byte)
+
+		if !ok {
+			return nil, fmt.Errorf("wrong value type for %s: %T", t.String(), v)
+		}
+
+		if len(b) != t.Size {
+			return nil, fmt.Errorf("wrong value length for %s: %d", t.String(), len(b))
+		}
+
+		return common.RightPadBytes(b, 32), nil
+
+	default:
+		return nil, fmt.Errorf("abi: unknown type %v", t.T)
+	}
+}
+
+func toUint256(v interface{}) (*uint256.Int, error) {
+	switch v := v.(type) {
+	case *uint256.Int:
+		if v == nil {
+			return nil, fmt.Errorf("nil integer value")
+		}
+
+		return v, nil
+
+	case *big.Int:
+		if v == nil {
+			return nil, fmt.Errorf("nil integer value")
+		}
+
+		i, overflow := uint256.FromBig(v)
+
+		if overflow {
+			return nil, fmt.Errorf("integer larger than 256 bits")
+		}
+
+		return i, nil
+
+	default:
+		return nil, fmt.Errorf("wrong value type for integer: %T", v)
+	}
+}
+
+func fitsInt(i *uint256.Int, size int) bool {
+	if size >= 256 {
+		return true
+	}
+
+	return uint256.NewInt(0).ExtendSign(i, uint256.NewInt(uint64(size/8-1))).Eq(i)
+}
+
+func packUint64(n uint64) []byte {
+	var b = uint256.NewInt(n).Bytes32()
+	return b[:]
+}
+
+func packBytes(b []byte) []byte {
+	var padded = common.RightPadBytes(b, (len(b)+31)/32*32)
+	return append(packUint64(uint64(len(b))), padded...)
+}
+
+func pullEvent(next NextFunc, typ EventType) (*Event, error) {
+	var evt, err, ok = next()
+
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of event sequence; wanted %s", typ)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if evt.Type != typ {
+		return nil, fmt.Errorf("wrong event type; wanted %s but got %s", typ, evt.Type)
+	}
+
+	return evt, nil
+}
+
+// encodeInPlace produces the encoding used to hash indexed reference types:
+// elements are padded to 32 bytes and concatenated, without offsets nor length prefixes
+func encodeInPlace(
+	next NextFunc,
+	t eth_abi.Type,
+	nested bool,
+) ([]byte, error) {
+	switch t.T {
+	case eth_abi.TupleTy:
+		evt, err := pullEvent(next, TupleStart)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if evt.Len != len(t.TupleElems) {
+			return nil, fmt.Errorf("wrong number of tuple elements for %s; wanted %d but got %d", t.String(), len(t.TupleElems), evt.Len)
+		}
+
+		var res []byte
+
+		for i, elem := range t.TupleElems {
+			evt, err := pullEvent(next, Key)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if evt.Index != i {
+				return nil, fmt.Errorf("wrong key index; wanted %d but got %d", i, evt.Index)
+			}
+
+			b, err := encodeInPlace(next, *elem, true)
+
+			if err != nil {
+				return nil, err
+			}
+
+			res = append(res, b...)
+		}
+
+		if _, err := pullEvent(next, TupleEnd); err != nil {
+			return nil, err
+		}
+
+		return res, nil
+
+	case eth_abi.ArrayTy, eth_abi.SliceTy:
+		evt, err := pullEvent(next, ArrayStart)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if t.T == eth_abi.ArrayTy && evt.Len != t.Size {
+			return nil, fmt.Errorf("wrong array length for %s; wanted %d but got %d", t.String(), t.Size, evt.Len)
+		}
+
+		var res []byte
+
+		for i := 0; i < evt.Len; i++ {
+			b, err := encodeInPlace(next, *t.Elem, true)
+
+			if err != nil {
+				return nil, err
+			}
+
+			res = append(res, b...)
+		}
+
+		if _, err := pullEvent(next, ArrayEnd); err != nil {
+			return nil, err
+		}
+
+		return res, nil
+
+	case eth_abi.StringTy, eth_abi.BytesTy:
+		evt, err := pullEvent(next, Value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		var b []byte
+
+		switch v := evt.Value.(type) {
+		case string:
+			b = []byte(v)
+		case []byte:
+			b = v
+		default:
+			return nil, fmt.Errorf("wrong value type for %s: %T", t.String(), evt.Value)
+		}
+
+		if nested {
+			return common.RightPadBytes(b, (len(b)+31)/32*32), nil
+		}
+
+		return b, nil
+
+	default:
+		evt, err := pullEvent(next, Value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return encodeScalar(t, evt.Value)
+	}
+}
+
+func encodeTopic(
+	next NextFunc,
+	t eth_abi.Type,
+) ([32]byte, error) {
+	if !isHashedTopic(t) {
+		b, err := encodeValue(next, t)
+
+		if err != nil {
+			return [32]byte{}, err
+		}
+
+		if len(b) != 32 {
+			return [32]byte{}, fmt.Errorf("wrong topic length for %s: %d", t.String(), len(b))
+		}
+
+		return [32]byte(b), nil
+	}
+
+	// the value may already be hashed, as when re-encoding a decoded log
+	var evt, err, ok = next()
+
+	if ok && err == nil && evt.Type == Value {
+		if h, isHash := evt.Value.(TopicHash); isHash {
+			return h, nil
+		}
+	}
+
+	b, err := encodeInPlace(unread(next, evt, err, ok), t, false)
+
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return crypto.Keccak256Hash(b), nil
+}
+
+// unread returns a NextFunc which yields the given, already pulled, event before resuming next
+func unread(next NextFunc, evt *Event, err error, ok bool) NextFunc {
+	var done bool
+
+	return func() (*Event, error, bool) {
+		if done {
+			return next()
+		}
+
+		done = true
+		return evt, err, ok
+	}
+}
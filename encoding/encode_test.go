@@ -0,0 +1,135 @@
+package encoding
+
+import (
+	"math/big"
+	"testing"
+
+	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+type encodeTestDataItem struct {
+	Name   string
+	Args   eth_abi.Arguments
+	Values []interface{}
+}
+
+func newArgs(ms ...eth_abi.ArgumentMarshaling) eth_abi.Arguments {
+	return lo.Map(ms, func(m eth_abi.ArgumentMarshaling, _ int) eth_abi.Argument {
+		return eth_abi.Argument{
+			Name: m.Name,
+			Type: lo.Must(eth_abi.NewType(m.Type, m.InternalType, m.Components)),
+		}
+	})
+}
+
+var encodeTestData = []encodeTestDataItem{
+	{
+		Name: "scalars",
+		Args: newArgs(
+			eth_abi.ArgumentMarshaling{Name: "a", Type: "uint8"},
+			eth_abi.ArgumentMarshaling{Name: "b", Type: "int32"},
+			eth_abi.ArgumentMarshaling{Name: "c", Type: "bool"},
+			eth_abi.ArgumentMarshaling{Name: "d", Type: "address"},
+			eth_abi.ArgumentMarshaling{Name: "e", Type: "bytes4"},
+		),
+		Values: []interface{}{
+			uint8(255),
+			int32(-42),
+			true,
+			common.HexToAddress("0xe38fe38eb33950e21fa9419178a27c9be553330a"),
+			[4]byte{1, 2, 3, 4},
+		},
+	},
+	{
+		Name: "dynamic",
+		Args: newArgs(
+			eth_abi.ArgumentMarshaling{Name: "a", Type: "string"},
+			eth_abi.ArgumentMarshaling{Name: "b", Type: "bytes"},
+			eth_abi.ArgumentMarshaling{Name: "c", Type: "uint256[]"},
+			eth_abi.ArgumentMarshaling{Name: "d", Type: "string[2]"},
+		),
+		Values: []interface{}{
+			"hello world",
+			[]byte("a string longer than thirty-two bytes, for padding"),
+			[]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)},
+			[2]string{"a", "bc"},
+		},
+	},
+	{
+		Name: "tuples",
+		Args: newArgs(
+			eth_abi.ArgumentMarshaling{Name: "a", Type: "tuple", Components: []eth_abi.ArgumentMarshaling{
+				{Name: "x", Type: "uint256"},
+				{Name: "y", Type: "uint256"},
+			}},
+			eth_abi.ArgumentMarshaling{Name: "b", Type: "uint256"},
+			eth_abi.ArgumentMarshaling{Name: "c", Type: "tuple[]", Components: []eth_abi.ArgumentMarshaling{
+				{Name: "x", Type: "string"},
+				{Name: "y", Type: "int256[2]"},
+			}},
+		),
+		Values: []interface{}{
+			struct{ X, Y *big.Int }{big.NewInt(1), big.NewInt(2)},
+			big.NewInt(3),
+			[]struct {
+				X string
+				Y [2]*big.Int
+			}{
+				{"hello", [2]*big.Int{big.NewInt(-1), big.NewInt(1)}},
+				{"world", [2]*big.Int{big.NewInt(-2), big.NewInt(2)}},
+			},
+		},
+	},
+	{
+		Name: "functions",
+		Args: newArgs(
+			eth_abi.ArgumentMarshaling{Name: "a", Type: "tuple", Components: []eth_abi.ArgumentMarshaling{
+				{Name: "x", Type: "address"},
+				{Name: "y", Type: "function"},
+			}},
+			eth_abi.ArgumentMarshaling{Name: "b", Type: "function[]"},
+		),
+		Values: []interface{}{
+			struct {
+				X common.Address
+				Y [24]byte
+			}{common.HexToAddress("0xe38fe38eb33950e21fa9419178a27c9be553330a"), [24]byte{1, 2, 3}},
+			[][24]byte{{4, 5, 6}, {7, 8, 9}},
+		},
+	},
+}
+
+func TestEncodeArgumentsRoundTrip(t *testing.T) {
+	for _, item := range encodeTestData {
+		t.Run(item.Name, func(t *testing.T) {
+			data, err := item.Args.Pack(item.Values...)
+			assert.NoError(t, err)
+			res, err := EncodeArguments(DecodeArguments(data, item.Args), item.Args)
+			assert.NoError(t, err)
+			assert.Equal(t, data, res)
+		})
+	}
+}
+
+func TestEncodeValueErrors(t *testing.T) {
+	var uint8Ty = lo.Must(eth_abi.NewType("uint8", "", nil))
+
+	_, err := EncodeValue(func(yield func(*Event, error) bool) {
+		yield(&Event{Type: Value, Value: big.NewInt(256)}, nil)
+	}, uint8Ty)
+	assert.Error(t, err)
+
+	_, err = EncodeValue(func(yield func(*Event, error) bool) {
+		yield(&Event{Type: Value, Value: "1"}, nil)
+	}, uint8Ty)
+	assert.Error(t, err)
+
+	_, err = EncodeValue(func(yield func(*Event, error) bool) {
+		_ = yield(&Event{Type: Value, Value: big.NewInt(1)}, nil) &&
+			yield(&Event{Type: Value, Value: big.NewInt(1)}, nil)
+	}, uint8Ty)
+	assert.Error(t, err)
+}
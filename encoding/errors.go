@@ -1,8 +1,72 @@
 package encoding
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
 
 var (
-	ErrIterStop               = errors.New("iter stop")
+	ErrIterStop = errors.New("iter stop")
+
+	// Deprecated: indexed reference type arguments are decoded to a TopicHash and this error is no longer returned
 	ErrDynamicIndexedArgument = errors.New("dynamic indexed argument")
+
+	ErrNonCanonicalEncoding = errors.New("non-canonical encoding")
+	ErrLimitExceeded        = errors.New("limit exceeded")
+	ErrIDMismatch           = errors.New("id mismatch")
 )
+
+// IDMismatchError reports a selector or topic0 that does not match the ABI fragment used to decode
+type IDMismatchError struct {
+	// Kind is "selector" or "topic0"
+	Kind     string
+	Expected []byte
+	Actual   []byte
+}
+
+func (e *IDMismatchError) Error() string {
+	return fmt.Sprintf("%s mismatch: wanted %s but got %s", e.Kind, hexutil.Encode(e.Expected), hexutil.Encode(e.Actual))
+}
+
+func (e *IDMismatchError) Unwrap() error {
+	return ErrIDMismatch
+}
+
+// DecodeError locates a decoding failure in the input data and in the decoded value
+type DecodeError struct {
+	// Offset is the position in the ABI-encoded data of the value that failed to decode
+	Offset int
+	Type   eth_abi.Type
+	// Path is the location of the value, e.g. orders[3].amount
+	Path string
+	Err  error
+}
+
+func (e *DecodeError) Error() string {
+	var path = e.Path
+
+	if len(path) == 0 {
+		path = "<root>"
+	}
+
+	return fmt.Sprintf("failed to decode %s at %s (offset %d): %s", e.Type.String(), path, e.Offset, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// PrependPath prefixes the value path, e.g. with the section ("inputs", "outputs") the arguments belong to
+func (e *DecodeError) PrependPath(prefix string) {
+	switch {
+	case len(e.Path) == 0:
+		e.Path = prefix
+	case e.Path[0] == '[':
+		e.Path = prefix + e.Path
+	default:
+		e.Path = prefix + "." + e.Path
+	}
+}
@@ -2,11 +2,11 @@ package encoding
 
 import (
 	"errors"
+	"fmt"
 	"iter"
 
-	"github.com/agnosticeng/panicsafe"
-
 	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 type EventType string
@@ -29,6 +29,10 @@ type Event struct {
 	Value   interface{}
 }
 
+// TopicHash is the value of an indexed reference type argument (string, bytes, array or tuple),
+// of which a log only holds the keccak256 hash of the encoded value
+type TopicHash common.Hash
+
 type YieldFunc func(*Event, error) bool
 
 func Yield(fn YieldFunc, evt *Event) error {
@@ -39,11 +43,23 @@ func Yield(fn YieldFunc, evt *Event) error {
 	return nil
 }
 
-func DecodeArguments(data []byte, args eth_abi.Arguments) iter.Seq2[*Event, error] {
+func DecodeArguments(data []byte, args eth_abi.Arguments, opts ...DecodeOption) iter.Seq2[*Event, error] {
+	var o = NewDecodeOptions(opts...)
+
 	return func(yield func(*Event, error) bool) {
-		var err = panicsafe.Func(func() error {
-			return decodeArguments(data, args, yield)
-		})()
+		var err = func() error {
+			if err := checkArgumentTypes(args); err != nil {
+				return err
+			}
+
+			if o.Strict {
+				if err := checkCanonicalArguments(data, args, newCheckState(o)); err != nil {
+					return err
+				}
+			}
+
+			return decodeArguments(data, args, yield, newDecodeState(o))
+		}()
 
 		if err == nil || errors.Is(err, ErrIterStop) {
 			return
@@ -53,11 +69,23 @@ func DecodeArguments(data []byte, args eth_abi.Arguments) iter.Seq2[*Event, erro
 	}
 }
 
-func DecodeValue(data []byte, t eth_abi.Type) iter.Seq2[*Event, error] {
+func DecodeValue(data []byte, t eth_abi.Type, opts ...DecodeOption) iter.Seq2[*Event, error] {
+	var o = NewDecodeOptions(opts...)
+
 	return func(yield func(*Event, error) bool) {
-		var err = panicsafe.Func(func() error {
-			return decodeValue(data, t, 0, yield)
-		})()
+		var err = func() error {
+			if _, err := isDynamic(t); err != nil {
+				return err
+			}
+
+			if o.Strict {
+				if err := checkCanonicalValue(data, t, newCheckState(o)); err != nil {
+					return err
+				}
+			}
+
+			return decodeValue(data, t, 0, yield, newDecodeState(o))
+		}()
 
 		if err == nil || errors.Is(err, ErrIterStop) {
 			return
@@ -66,3 +94,112 @@ func DecodeValue(data []byte, t eth_abi.Type) iter.Seq2[*Event, error] {
 		yield(nil, err)
 	}
 }
+
+// DecodeTopic decodes an indexed event argument; reference types yield a single TopicHash value
+func DecodeTopic(topic []byte, t eth_abi.Type, opts ...DecodeOption) iter.Seq2[*Event, error] {
+	if !isHashedTopic(t) {
+		return DecodeValue(topic, t, opts...)
+	}
+
+	var o = NewDecodeOptions(opts...)
+
+	return func(yield func(*Event, error) bool) {
+		if _, err := isDynamic(t); err != nil {
+			yield(nil, err)
+			return
+		}
+
+		if len(topic) != 32 {
+			yield(nil, fmt.Errorf("wrong topic length for %s: %d", t.String(), len(topic)))
+			return
+		}
+
+		if err := newDecodeState(o).countValue(); err != nil {
+			yield(nil, err)
+			return
+		}
+
+		yield(&Event{
+			Type:    Value,
+			ABIType: t,
+			Value:   TopicHash(common.BytesToHash(topic)),
+		}, nil)
+	}
+}
+
+func EncodeArguments(seq iter.Seq2[*Event, error], args eth_abi.Arguments) ([]byte, error) {
+	if err := checkArgumentTypes(args); err != nil {
+		return nil, err
+	}
+
+	var next, stop = iter.Pull2(seq)
+	defer stop()
+
+	res, err := encodeArguments(next, args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := expectEndOfSeq(next); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func EncodeValue(seq iter.Seq2[*Event, error], t eth_abi.Type) ([]byte, error) {
+	if _, err := isDynamic(t); err != nil {
+		return nil, err
+	}
+
+	var next, stop = iter.Pull2(seq)
+	defer stop()
+
+	res, err := encodeValue(next, t)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := expectEndOfSeq(next); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func EncodeTopic(seq iter.Seq2[*Event, error], t eth_abi.Type) ([32]byte, error) {
+	if _, err := isDynamic(t); err != nil {
+		return [32]byte{}, err
+	}
+
+	var next, stop = iter.Pull2(seq)
+	defer stop()
+
+	res, err := encodeTopic(next, t)
+
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	if err := expectEndOfSeq(next); err != nil {
+		return [32]byte{}, err
+	}
+
+	return res, nil
+}
+
+func expectEndOfSeq(next NextFunc) error {
+	var evt, err, ok = next()
+
+	if !ok {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("wanted end of event sequence but got %s", evt.Type)
+}
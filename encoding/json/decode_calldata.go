@@ -1,21 +1,27 @@
 package json
 
 import (
+	"bytes"
 	"fmt"
 
+	"github.com/agnosticeng/evmabi/encoding"
 	"github.com/bytedance/sonic/ast"
 	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
 )
 
-func DecodeCallData(data []byte, method eth_abi.Method) (ast.Node, error) {
+func DecodeCallData(data []byte, method eth_abi.Method, opts ...encoding.DecodeOption) (ast.Node, error) {
 	if len(data) < 4 {
 		return ast.Node{}, fmt.Errorf("call data is smaller than 4 bytes")
 	}
 
-	inputs, err := DecodeArguments(data[4:], method.Inputs)
+	if err := checkID("selector", method.ID, data[:4], opts); err != nil {
+		return ast.Node{}, err
+	}
+
+	inputs, err := DecodeArguments(data[4:], method.Inputs, opts...)
 
 	if err != nil {
-		return ast.Node{}, err
+		return ast.Node{}, prependPath(err, "inputs")
 	}
 
 	return ast.NewObject([]ast.Pair{
@@ -23,3 +29,16 @@ func DecodeCallData(data []byte, method eth_abi.Method) (ast.Node, error) {
 		ast.NewPair("inputs", inputs),
 	}), nil
 }
+
+// checkID verifies a selector or topic0 against the ID of the ABI fragment, unless disabled by the options
+func checkID(kind string, expected []byte, actual []byte, opts []encoding.DecodeOption) error {
+	if encoding.NewDecodeOptions(opts...).SkipIDCheck || bytes.Equal(expected, actual) {
+		return nil
+	}
+
+	return &encoding.IDMismatchError{
+		Kind:     kind,
+		Expected: expected,
+		Actual:   actual,
+	}
+}
@@ -0,0 +1,83 @@
+package json
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agnosticeng/evmabi/encoding"
+	"github.com/bytedance/sonic/ast"
+	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/samber/lo"
+)
+
+// DecodeConstructor decodes the constructor arguments appended to the contract creation bytecode, e.g. of an artifact.
+// The init code must start with the bytecode, except for linked library addresses, which are zero in the bytecode, and
+// the trailing CBOR metadata, which depends on the sources and not only on the code; use DecodeConstructorAt when the
+// init code was built from another bytecode, e.g. with other compiler settings.
+func DecodeConstructor(initcode []byte, bytecode []byte, ctor eth_abi.Method, opts ...encoding.DecodeOption) (ast.Node, error) {
+	if len(initcode) < len(bytecode) {
+		return ast.Node{}, fmt.Errorf("init code is shorter than contract creation bytecode (%d < %d)", len(initcode), len(bytecode))
+	}
+
+	if i := mismatchBytecode(initcode, bytecode); i >= 0 {
+		return ast.Node{}, fmt.Errorf("init code does not match contract creation bytecode at byte %d", i)
+	}
+
+	return DecodeConstructorAt(initcode, len(bytecode), ctor, opts...)
+}
+
+// DecodeConstructorAt decodes the constructor arguments starting at offset in the init code
+func DecodeConstructorAt(initcode []byte, offset int, ctor eth_abi.Method, opts ...encoding.DecodeOption) (ast.Node, error) {
+	if ctor.Type != eth_abi.Constructor {
+		return ast.Node{}, fmt.Errorf("method is not a constructor")
+	}
+
+	if offset < 0 || offset > len(initcode) {
+		return ast.Node{}, fmt.Errorf("constructor arguments offset %d is out of init code bounds (len=%d)", offset, len(initcode))
+	}
+
+	inputs, err := DecodeArguments(initcode[offset:], ctor.Inputs, opts...)
+
+	if err != nil {
+		return ast.Node{}, prependPath(err, "inputs")
+	}
+
+	return ast.NewObject([]ast.Pair{
+		ast.NewPair("signature", ast.NewString(constructorSig(ctor))),
+		ast.NewPair("inputs", inputs),
+	}), nil
+}
+
+// constructorSig mimics method signatures since go-ethereum leaves Sig empty for constructors
+func constructorSig(ctor eth_abi.Method) string {
+	var types = lo.Map(ctor.Inputs, func(arg eth_abi.Argument, _ int) string { return arg.Type.String() })
+	return "constructor(" + strings.Join(types, ",") + ")"
+}
+
+// mismatchBytecode returns the index of the first byte of the init code that differs from the bytecode, or -1.
+// PUSH20 operands that are zero in the bytecode, i.e. unlinked library addresses, and the trailing CBOR metadata,
+// whose length solc appends as 2 big-endian bytes, are not compared.
+func mismatchBytecode(initcode []byte, bytecode []byte) int {
+	var end = len(bytecode)
+
+	if end >= 2 {
+		var n = int(bytecode[end-2])<<8 | int(bytecode[end-1])
+
+		// metadata is a CBOR map, i.e. starts with 0xa0 to 0xbf
+		if n+2 <= end && n > 0 && bytecode[end-2-n]&0xe0 == 0xa0 {
+			end -= n + 2
+		}
+	}
+
+	for i := 0; i < end; i++ {
+		if bytecode[i] != initcode[i] {
+			return i
+		}
+
+		if bytecode[i] == 0x73 && i+21 <= end && lo.Every([]byte{0}, bytecode[i+1:i+21]) {
+			i += 20
+		}
+	}
+
+	return -1
+}
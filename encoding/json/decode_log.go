@@ -1,18 +1,41 @@
 package json
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 
+	"github.com/agnosticeng/evmabi/encoding"
 	"github.com/bytedance/sonic/ast"
 	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
 )
 
-func DecodeLog(topics [][32]byte, input []byte, event eth_abi.Event) (ast.Node, error) {
+func DecodeLog(topics [][32]byte, input []byte, event eth_abi.Event, opts ...encoding.DecodeOption) (ast.Node, error) {
+	// the limits apply to the whole log, topics and data
+	opts = append(slices.Clip(opts), encoding.WithSharedLimits())
+
 	var indexed, unindexed = SplitInputs(event.Inputs)
 
-	// mismatch btw num of indexed fields and num of topics
-	if len(indexed) != (len(topics) - 1) {
-		return ast.Node{}, fmt.Errorf("event has %d indexed inputs but log has %d topics", len(indexed), len(topics))
+	// a log has at most 4 topics; anonymous events do not spend one on the signature
+	if len(topics) > 4 {
+		return ast.Node{}, fmt.Errorf("log has %d topics", len(topics))
+	}
+
+	if event.Anonymous {
+		if len(indexed) != len(topics) {
+			return ast.Node{}, fmt.Errorf("anonymous event has %d indexed inputs but log has %d topics", len(indexed), len(topics))
+		}
+	} else {
+		// mismatch btw num of indexed fields and num of topics
+		if len(indexed) != (len(topics) - 1) {
+			return ast.Node{}, fmt.Errorf("event has %d indexed inputs but log has %d topics", len(indexed), len(topics))
+		}
+
+		if err := checkID("topic0", event.ID[:], topics[0][:], opts); err != nil {
+			return ast.Node{}, err
+		}
+
+		topics = topics[1:]
 	}
 
 	// log has data but abi field does not have unindexed fields
@@ -20,10 +43,10 @@ func DecodeLog(topics [][32]byte, input []byte, event eth_abi.Event) (ast.Node,
 		return ast.Node{}, fmt.Errorf("event have unindexed inputs but log has no data")
 	}
 
-	inputs, err := DecodeArguments(input, unindexed)
+	inputs, err := DecodeArguments(input, unindexed, opts...)
 
 	if err != nil {
-		return ast.Node{}, fmt.Errorf("failed to decode non-indexed fields: %w", err)
+		return ast.Node{}, fmt.Errorf("failed to decode non-indexed fields: %w", prependPath(err, "inputs"))
 	}
 
 	length, err := inputs.Len()
@@ -37,10 +60,10 @@ func DecodeLog(topics [][32]byte, input []byte, event eth_abi.Event) (ast.Node,
 	}
 
 	for i, input := range indexed {
-		v, err := DecodeValue(topics[i+1][:], input.Type)
+		v, err := DecodeTopic(topics[i][:], input.Type, opts...)
 
 		if err != nil {
-			return ast.Node{}, err
+			return ast.Node{}, prependPath(err, "inputs."+input.Name)
 		}
 
 		inputs.Set(input.Name, v)
@@ -52,6 +75,58 @@ func DecodeLog(topics [][32]byte, input []byte, event eth_abi.Event) (ast.Node,
 	}), nil
 }
 
+// DecodeLogVariants decodes a log with the first candidate event whose indexed inputs match the topics
+// and whose unindexed inputs match the data length, e.g. to tell the ERC-20 and ERC-721 Transfer events apart.
+// It returns the index of the matching candidate.
+func DecodeLogVariants(topics [][32]byte, input []byte, events []eth_abi.Event, opts ...encoding.DecodeOption) (ast.Node, int, error) {
+	var errs []error
+
+	for i, event := range events {
+		if err := matchLogLayout(topics, input, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", event.Sig, err))
+			continue
+		}
+
+		node, err := DecodeLog(topics, input, event, opts...)
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", event.Sig, err))
+			continue
+		}
+
+		return node, i, nil
+	}
+
+	return ast.Node{}, -1, fmt.Errorf("%w: %w", ErrNoMatchingEvent, errors.Join(errs...))
+}
+
+func matchLogLayout(topics [][32]byte, input []byte, event eth_abi.Event) error {
+	var (
+		indexed, unindexed = SplitInputs(event.Inputs)
+		numTopics          = len(indexed)
+	)
+
+	if !event.Anonymous {
+		numTopics++
+	}
+
+	if numTopics != len(topics) {
+		return fmt.Errorf("event has %d indexed inputs but log has %d topics", len(indexed), len(topics))
+	}
+
+	size, dynamic, err := encoding.HeadSize(unindexed)
+
+	if err != nil {
+		return err
+	}
+
+	if len(input) < size || !dynamic && len(input) != size {
+		return fmt.Errorf("event has %d bytes of unindexed inputs but log has %d bytes of data", size, len(input))
+	}
+
+	return nil
+}
+
 func SplitInputs(inputs []eth_abi.Argument) ([]eth_abi.Argument, []eth_abi.Argument) {
 	var (
 		indexed   []eth_abi.Argument
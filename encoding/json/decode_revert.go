@@ -0,0 +1,79 @@
+package json
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/agnosticeng/evmabi/encoding"
+	"github.com/bytedance/sonic/ast"
+	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/holiman/uint256"
+	"github.com/samber/lo"
+)
+
+var (
+	// BuiltinError is the error emitted by require(cond, "message") and revert("message")
+	BuiltinError = eth_abi.NewError("Error", eth_abi.Arguments{
+		{Name: "message", Type: lo.Must(eth_abi.NewType("string", "", nil))},
+	})
+
+	// BuiltinPanic is the error emitted by assert(cond) and runtime checks
+	BuiltinPanic = eth_abi.NewError("Panic", eth_abi.Arguments{
+		{Name: "code", Type: lo.Must(eth_abi.NewType("uint256", "", nil))},
+	})
+
+	PanicReasons = map[uint64]string{
+		0x00: "generic compiler inserted panic",
+		0x01: "assertion failed",
+		0x11: "arithmetic underflow or overflow",
+		0x12: "division or modulo by zero",
+		0x21: "conversion to enum out of range",
+		0x22: "incorrectly encoded storage byte array",
+		0x31: "pop on empty array",
+		0x32: "array index out of bounds",
+		0x41: "too much memory allocated",
+		0x51: "call to zero-initialized internal function",
+	}
+)
+
+func DecodeRevert(data []byte, errors []eth_abi.Error, opts ...encoding.DecodeOption) (ast.Node, error) {
+	if len(data) < 4 {
+		return ast.Node{}, fmt.Errorf("revert data is smaller than 4 bytes")
+	}
+
+	var e, found = lo.Find(append([]eth_abi.Error{BuiltinError, BuiltinPanic}, errors...), func(e eth_abi.Error) bool {
+		return bytes.Equal(e.ID[:4], data[:4])
+	})
+
+	if !found {
+		return ast.Node{}, fmt.Errorf("unknown error selector: %s", hexutil.Encode(data[:4]))
+	}
+
+	inputs, err := DecodeArguments(data[4:], e.Inputs, opts...)
+
+	if err != nil {
+		return ast.Node{}, prependPath(err, "inputs")
+	}
+
+	var pairs = []ast.Pair{
+		ast.NewPair("signature", ast.NewString(e.Sig)),
+		ast.NewPair("inputs", inputs),
+	}
+
+	if e.ID == BuiltinPanic.ID {
+		pairs = append(pairs, ast.NewPair("reason", ast.NewString(PanicReason(uint256.NewInt(0).SetBytes(data[4:36])))))
+	}
+
+	return ast.NewObject(pairs), nil
+}
+
+func PanicReason(code *uint256.Int) string {
+	if code.IsUint64() {
+		if reason, ok := PanicReasons[code.Uint64()]; ok {
+			return reason
+		}
+	}
+
+	return "unknown panic code " + code.Hex()
+}
@@ -4,10 +4,18 @@ import (
 	"bytes"
 	_ "embed"
 	"encoding/json"
+	"math/big"
+	"strings"
 	"testing"
 
+	"github.com/agnosticeng/evmabi/abi"
+	"github.com/agnosticeng/evmabi/encoding"
+	"github.com/agnosticeng/evmabi/fullsig"
+	"github.com/bytedance/sonic/ast"
 	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
 	"github.com/swaggest/assertjson"
 
@@ -86,3 +94,436 @@ func TestDecodeLog(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeRevert(t *testing.T) {
+	var (
+		custom = lo.Must(abi.JSONError([]byte(`{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]}`)))
+		tests  = []struct {
+			name   string
+			data   []byte
+			result string
+		}{
+			{
+				name:   "Error",
+				data:   append(BuiltinError.ID[:4:4], lo.Must(BuiltinError.Inputs.Pack("Not enough Ether"))...),
+				result: `{"signature":"Error(string)","inputs":{"message":"Not enough Ether"}}`,
+			},
+			{
+				name:   "Panic",
+				data:   append(BuiltinPanic.ID[:4:4], lo.Must(BuiltinPanic.Inputs.Pack(big.NewInt(0x11)))...),
+				result: `{"signature":"Panic(uint256)","inputs":{"code":"17"},"reason":"arithmetic underflow or overflow"}`,
+			},
+			{
+				name:   "InsufficientBalance",
+				data:   append(custom.ID[:4:4], lo.Must(custom.Inputs.Pack(big.NewInt(1), big.NewInt(2)))...),
+				result: `{"signature":"InsufficientBalance(uint256,uint256)","inputs":{"available":"1","required":"2"}}`,
+			},
+		}
+	)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			node, err := DecodeRevert(test.data, []eth_abi.Error{*custom})
+			assert.NoError(t, err)
+			js, err := node.MarshalJSON()
+			assert.NoError(t, err)
+			assertjson.Equal(t, []byte(test.result), js)
+		})
+	}
+
+	_, err := DecodeRevert(hexutil.MustDecode("0xdeadbeef"), []eth_abi.Error{*custom})
+	assert.Error(t, err)
+}
+
+func TestDecodeTraceWithStatus(t *testing.T) {
+	var (
+		method = _abi.Methods["transfer"]
+		input  = append(method.ID[:4:4], lo.Must(method.Inputs.Pack(common.HexToAddress("0xe38fe38eb33950e21fa9419178a27c9be553330a"), big.NewInt(1000)))...)
+		output = append(BuiltinError.ID[:4:4], lo.Must(BuiltinError.Inputs.Pack("ERC20: transfer amount exceeds balance"))...)
+	)
+
+	node, err := DecodeTraceWithStatus(input, output, true, method, nil)
+	assert.NoError(t, err)
+	js, err := node.MarshalJSON()
+	assert.NoError(t, err)
+	assertjson.Equal(t, []byte(`{
+		"signature": "transfer(address,uint256)",
+		"inputs": {"recipient": "0xe38fe38eb33950e21fa9419178a27c9be553330a", "amount": "1000"},
+		"error": {"signature": "Error(string)", "inputs": {"message": "ERC20: transfer amount exceeds balance"}}
+	}`), js)
+
+	node, err = DecodeTraceWithStatus(input, nil, true, method, nil)
+	assert.NoError(t, err)
+	js, err = node.MarshalJSON()
+	assert.NoError(t, err)
+	assertjson.Equal(t, []byte(`{
+		"signature": "transfer(address,uint256)",
+		"inputs": {"recipient": "0xe38fe38eb33950e21fa9419178a27c9be553330a", "amount": "1000"},
+		"error": null
+	}`), js)
+}
+
+func TestDecodeConstructor(t *testing.T) {
+	var (
+		ctor     = lo.Must(abi.JSONConstructor([]byte(`{"type":"constructor","stateMutability":"nonpayable","inputs":[{"name":"name","type":"string"},{"name":"owner","type":"address"},{"name":"fee","type":"uint24"}]}`)))
+		bytecode = hexutil.MustDecode("0x6080604052348015600f57600080fd5b50a165627a7a72305820" + strings.Repeat("ab", 32) + "0029")
+		args     = lo.Must(ctor.Inputs.Pack("Token", common.HexToAddress("0xe38fe38eb33950e21fa9419178a27c9be553330a"), big.NewInt(3000)))
+		initcode = append(bytecode[:len(bytecode):len(bytecode)], args...)
+		result   = []byte(`{
+			"signature": "constructor(string,address,uint24)",
+			"inputs": {"name": "Token", "owner": "0xe38fe38eb33950e21fa9419178a27c9be553330a", "fee": "3000"}
+		}`)
+	)
+
+	node, err := DecodeConstructor(initcode, bytecode, *ctor)
+	assert.NoError(t, err)
+	js, err := node.MarshalJSON()
+	assert.NoError(t, err)
+	assertjson.Equal(t, result, js)
+
+	node, err = DecodeConstructorAt(initcode, len(bytecode), *ctor)
+	assert.NoError(t, err)
+	js, err = node.MarshalJSON()
+	assert.NoError(t, err)
+	assertjson.Equal(t, result, js)
+
+	// the metadata hash changes with the sources, e.g. comments, but not the code
+	var rebuilt = bytes.Clone(initcode)
+	rebuilt[len(bytecode)-10] = 0xff
+
+	node, err = DecodeConstructor(rebuilt, bytecode, *ctor)
+	assert.NoError(t, err)
+	js, err = node.MarshalJSON()
+	assert.NoError(t, err)
+	assertjson.Equal(t, result, js)
+
+	// other compiler settings change the code
+	var other = bytes.Clone(initcode)
+	other[4] = 0xff
+
+	_, err = DecodeConstructor(other, bytecode, *ctor)
+	assert.Error(t, err)
+
+	node, err = DecodeConstructorAt(other, len(bytecode), *ctor)
+	assert.NoError(t, err)
+	js, err = node.MarshalJSON()
+	assert.NoError(t, err)
+	assertjson.Equal(t, result, js)
+
+	_, err = DecodeConstructor(initcode[:len(bytecode)-1], bytecode, *ctor)
+	assert.Error(t, err)
+
+	_, err = DecodeConstructor(initcode, hexutil.MustDecode("0x6060"), *ctor)
+	assert.Error(t, err)
+}
+
+func TestDecodeConstructorArtifact(t *testing.T) {
+	var (
+		artifact = lo.Must(abi.ParseArtifact([]byte(`{
+			"_format": "hh-sol-artifact-1",
+			"contractName": "Token",
+			"sourceName": "contracts/Token.sol",
+			"abi": [{"type":"constructor","stateMutability":"nonpayable","inputs":[{"name":"name","type":"string"},{"name":"fee","type":"uint24"}]}],
+			"bytecode": "0x608060405273__$0123456789abcdef0123456789abcdef01$__5b50"
+		}`)))
+		linked   = hexutil.MustDecode("0x608060405273e38fe38eb33950e21fa9419178a27c9be553330a5b50")
+		args     = lo.Must(artifact.ABI.Constructor.Inputs.Pack("Token", big.NewInt(3000)))
+		initcode = append(linked, args...)
+	)
+
+	assert.Len(t, artifact.Bytecode, len(linked))
+
+	node, err := DecodeConstructor(initcode, artifact.Bytecode, artifact.ABI.Constructor)
+	assert.NoError(t, err)
+	js, err := node.MarshalJSON()
+	assert.NoError(t, err)
+	assertjson.Equal(t, []byte(`{"signature": "constructor(string,uint24)", "inputs": {"name": "Token", "fee": "3000"}}`), js)
+
+	// the library address is not compared, the code around it is
+	initcode[len(linked)-1] = 0x00
+	_, err = DecodeConstructor(initcode, artifact.Bytecode, artifact.ABI.Constructor)
+	assert.Error(t, err)
+}
+
+func TestDecodeStrict(t *testing.T) {
+	var (
+		method = _abi.Methods["transfer"]
+		input  = append(method.ID[:4:4], lo.Must(method.Inputs.Pack(common.HexToAddress("0xe38fe38eb33950e21fa9419178a27c9be553330a"), big.NewInt(1000)))...)
+		dirty  = common.CopyBytes(input)
+	)
+
+	// garbage in the upper 12 bytes of the address
+	dirty[4] = 0xff
+
+	_, err := DecodeCallData(input, method, encoding.WithStrict(true))
+	assert.NoError(t, err)
+
+	_, err = DecodeCallData(dirty, method)
+	assert.NoError(t, err)
+
+	_, err = DecodeCallData(dirty, method, encoding.WithStrict(true))
+	assert.ErrorIs(t, err, encoding.ErrNonCanonicalEncoding)
+
+	_, err = DecodeCallData(append(input, 0), method, encoding.WithStrict(true))
+	assert.ErrorIs(t, err, encoding.ErrNonCanonicalEncoding)
+
+	for _, trace := range traceTestData {
+		_, err := DecodeTrace(hexutil.MustDecode(trace.Input), nil, _abi.Methods[trace.MethodName], encoding.WithStrict(true))
+		assert.NoError(t, err)
+	}
+}
+
+func TestDecodeLimits(t *testing.T) {
+	var (
+		word = func(n int) []byte { return common.LeftPadBytes(big.NewInt(int64(n)).Bytes(), 32) }
+		args = func(typ string) eth_abi.Arguments {
+			return eth_abi.Arguments{{Name: "x", Type: lo.Must(eth_abi.NewType(typ, "", nil))}}
+		}
+		nested = word(32)
+		blobs  = word(32)
+	)
+
+	// an outer array of 20 elements all pointing to the same inner array of 20 elements
+	nested = append(nested, word(20)...)
+
+	for i := 0; i < 20; i++ {
+		nested = append(nested, word(20*32)...)
+	}
+
+	nested = append(nested, word(20)...)
+
+	for i := 0; i < 20; i++ {
+		nested = append(nested, word(i)...)
+	}
+
+	// 10 bytes elements all pointing to the same 1000 bytes tail
+	blobs = append(blobs, word(10)...)
+
+	for i := 0; i < 10; i++ {
+		blobs = append(blobs, word(10*32)...)
+	}
+
+	blobs = append(blobs, word(1000)...)
+	blobs = append(blobs, make([]byte, 1024)...)
+
+	var tests = []struct {
+		name   string
+		data   []byte
+		args   eth_abi.Arguments
+		limits encoding.Limits
+	}{
+		{"MaxValues", nested, args("uint256[][]"), encoding.Limits{MaxValues: 100}},
+		{"MaxDepth", nested, args("uint256[][]"), encoding.Limits{MaxDepth: 2}},
+		{"MaxArrayLength", nested, args("uint256[][]"), encoding.Limits{MaxArrayLength: 10}},
+		{"MaxBytes", blobs, args("bytes[]"), encoding.Limits{MaxBytes: 5000}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := DecodeArguments(test.data, test.args)
+			assert.NoError(t, err)
+
+			_, err = DecodeArguments(test.data, test.args, encoding.WithLimits(test.limits))
+			assert.ErrorIs(t, err, encoding.ErrLimitExceeded)
+		})
+	}
+}
+
+// the limits bound a whole log or call, even when each of its parts is within them
+func TestDecodeSharedLimits(t *testing.T) {
+	var (
+		event  = lo.Must(fullsig.ParseEvent("event LogNote(bytes4 indexed,address indexed,bytes32 indexed,bytes32 indexed,uint256,bytes) anonymous"))
+		method = lo.Must(fullsig.ParseMethod("function echo(bytes) returns (bytes)"))
+		word   = common.LeftPadBytes([]byte{1}, 32)
+	)
+
+	topics, data, err := EncodeLog(ast.NewRaw(`{"arg0": "0x12345678", "arg1": "0x0000000000000000000000000000000000000001", "arg2": "0x0000000000000000000000000000000000000000000000000000000000000001", "arg3": "0x0000000000000000000000000000000000000000000000000000000000000002", "arg4": "3", "arg5": "0x1234"}`), event)
+	assert.NoError(t, err)
+
+	var limits = encoding.WithLimits(encoding.Limits{MaxValues: 5})
+
+	_, err = DecodeArguments(data, lo.Filter(event.Inputs, func(arg eth_abi.Argument, _ int) bool { return !arg.Indexed }), limits)
+	assert.NoError(t, err)
+	_, err = DecodeTopic(topics[0][:], event.Inputs[0].Type, limits)
+	assert.NoError(t, err)
+	_, err = DecodeLog(topics, data, event, limits)
+	assert.ErrorIs(t, err, encoding.ErrLimitExceeded)
+
+	var (
+		input  = append(method.ID, lo.Must(method.Inputs.Pack(bytes.Repeat(word, 2)))...)
+		output = lo.Must(method.Outputs.Pack(bytes.Repeat(word, 2)))
+	)
+
+	limits = encoding.WithLimits(encoding.Limits{MaxBytes: 100})
+
+	_, err = DecodeArguments(output, method.Outputs, limits)
+	assert.NoError(t, err)
+	_, err = DecodeTrace(input, output, method, limits)
+	assert.ErrorIs(t, err, encoding.ErrLimitExceeded)
+	_, err = DecodeTrace(input, output, method, limits, encoding.WithStrict(true))
+	assert.ErrorIs(t, err, encoding.ErrLimitExceeded)
+
+	// the strict mode check pass does not spend the budget
+	_, err = DecodeCallData(input, method, limits, encoding.WithStrict(true))
+	assert.NoError(t, err)
+}
+
+func TestDecodeError(t *testing.T) {
+	var (
+		trace  = traceTestData[0]
+		method = _abi.Methods[trace.MethodName]
+		input  = hexutil.MustDecode(trace.Input)
+	)
+
+	// corrupt the length of the "amounts" array (its tail starts at 0x140)
+	input[4+0x140] = 0xff
+
+	_, err := DecodeTrace(input, nil, method)
+
+	var decodeErr *encoding.DecodeError
+	assert.ErrorAs(t, err, &decodeErr)
+	assert.Equal(t, "inputs.amounts", decodeErr.Path)
+	assert.Equal(t, 0x60, decodeErr.Offset)
+	assert.Equal(t, "uint256[]", decodeErr.Type.String())
+
+	var (
+		event  = lo.Must(fullsig.ParseEvent("event E(address indexed,(uint8,bool)[])"))
+		data   = append(common.LeftPadBytes([]byte{0x20}, 32), common.LeftPadBytes([]byte{2}, 32)...)
+		topics = [][32]byte{event.ID, [32]byte(common.LeftPadBytes([]byte{1}, 32))}
+	)
+
+	data = append(data, common.LeftPadBytes([]byte{1}, 32)...)
+	data = append(data, common.LeftPadBytes([]byte{1}, 32)...)
+	data = append(data, common.LeftPadBytes([]byte{1}, 32)...)
+	data = append(data, common.LeftPadBytes([]byte{2}, 32)...)
+
+	_, err = DecodeLog(topics, data, event)
+	assert.ErrorAs(t, err, &decodeErr)
+	assert.Equal(t, "inputs.arg1[1].arg1", decodeErr.Path)
+	assert.Equal(t, 0xa0, decodeErr.Offset)
+	assert.Equal(t, "bool", decodeErr.Type.String())
+}
+
+func TestDecodeFunction(t *testing.T) {
+	var (
+		method = lo.Must(fullsig.ParseMethod("function f((address,function),function[])"))
+		input  = hexutil.MustDecode(
+			hexutil.Encode(method.ID) +
+				"000000000000000000000000e38fe38eb33950e21fa9419178a27c9be553330a" +
+				"e38fe38eb33950e21fa9419178a27c9be553330aa9059cbb0000000000000000" +
+				"0000000000000000000000000000000000000000000000000000000000000060" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"e38fe38eb33950e21fa9419178a27c9be553330a23b872dd0000000000000000",
+		)
+	)
+
+	node, err := DecodeCallData(input, method, encoding.WithStrict(true))
+	assert.NoError(t, err)
+	js, err := node.MarshalJSON()
+	assert.NoError(t, err)
+	assertjson.Equal(t, []byte(`{
+		"signature": "f((address,function),function[])",
+		"inputs": {
+			"arg0": {"arg0": "0xe38fe38eb33950e21fa9419178a27c9be553330a", "arg1": "0xe38fe38eb33950e21fa9419178a27c9be553330aa9059cbb"},
+			"arg1": ["0xe38fe38eb33950e21fa9419178a27c9be553330a23b872dd"]
+		}
+	}`), js)
+
+	res, err := EncodeCallData(node, method)
+	assert.NoError(t, err)
+	assert.Equal(t, input, res)
+}
+
+func TestDecodeIDMismatch(t *testing.T) {
+	var (
+		method = lo.Must(fullsig.ParseMethod("function transfer(address,uint256)"))
+		other  = lo.Must(fullsig.ParseMethod("function approve(address,uint256)"))
+		event  = lo.Must(fullsig.ParseEvent("event Transfer(address indexed,address indexed,uint256)"))
+		input  = append(method.ID[:4:4], make([]byte, 64)...)
+		topics = [][32]byte{crypto.Keccak256Hash([]byte("Approval(address,address,uint256)")), {}, {}}
+		idErr  *encoding.IDMismatchError
+	)
+
+	_, err := DecodeCallData(input, other)
+	assert.ErrorIs(t, err, encoding.ErrIDMismatch)
+	assert.ErrorAs(t, err, &idErr)
+	assert.Equal(t, "selector", idErr.Kind)
+	assert.Equal(t, method.ID[:4], idErr.Actual)
+
+	_, err = DecodeTrace(input, nil, other)
+	assert.ErrorIs(t, err, encoding.ErrIDMismatch)
+
+	_, err = DecodeLog(topics, make([]byte, 32), event)
+	assert.ErrorAs(t, err, &idErr)
+	assert.Equal(t, "topic0", idErr.Kind)
+
+	_, err = DecodeCallData(input, other, encoding.WithIDCheck(false))
+	assert.NoError(t, err)
+
+	_, err = DecodeLog(topics, make([]byte, 32), event, encoding.WithIDCheck(false))
+	assert.NoError(t, err)
+}
+
+func TestDecodeLogAnonymous(t *testing.T) {
+	var (
+		event = lo.Must(fullsig.ParseEvent("event LogNote(bytes4 indexed,address indexed,bytes32 indexed,bytes32 indexed,uint256,bytes) anonymous"))
+		node  = ast.NewRaw(`{
+			"arg0": "0x12345678",
+			"arg1": "0xe38fe38eb33950e21fa9419178a27c9be553330a",
+			"arg2": "0x0000000000000000000000000000000000000000000000000000000000000001",
+			"arg3": "0x0000000000000000000000000000000000000000000000000000000000000002",
+			"arg4": "3",
+			"arg5": "0x1234"
+		}`)
+	)
+
+	topics, data, err := EncodeLog(node, event)
+	assert.NoError(t, err)
+	assert.Len(t, topics, 4)
+	assert.Equal(t, common.RightPadBytes([]byte{0x12, 0x34, 0x56, 0x78}, 32), topics[0][:])
+
+	decoded, err := DecodeLog(topics, data, event)
+	assert.NoError(t, err)
+	js, err := decoded.MarshalJSON()
+	assert.NoError(t, err)
+	assertjson.Equal(t, []byte(`{
+		"signature": "LogNote(bytes4,address,bytes32,bytes32,uint256,bytes)",
+		"inputs": {
+			"arg0": "0x12345678",
+			"arg1": "0xe38fe38eb33950e21fa9419178a27c9be553330a",
+			"arg2": "0x0000000000000000000000000000000000000000000000000000000000000001",
+			"arg3": "0x0000000000000000000000000000000000000000000000000000000000000002",
+			"arg4": "3",
+			"arg5": "0x1234"
+		}
+	}`), js)
+
+	_, err = DecodeLog(topics[:3], data, event)
+	assert.Error(t, err)
+}
+
+func TestDecodeLogVariants(t *testing.T) {
+	var (
+		erc20  = lo.Must(fullsig.ParseEvent("event Transfer(address indexed,address indexed,uint256)"))
+		erc721 = lo.Must(fullsig.ParseEvent("event Transfer(address indexed,address indexed,uint256 indexed)"))
+		events = []eth_abi.Event{erc20, erc721}
+		from   = common.BytesToHash(common.HexToAddress("0xe38fe38eb33950e21fa9419178a27c9be553330a").Bytes())
+		value  = common.BigToHash(big.NewInt(42))
+	)
+
+	assert.Equal(t, erc20.ID, erc721.ID)
+
+	node, idx, err := DecodeLogVariants([][32]byte{erc20.ID, from, from}, value[:], events)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, idx)
+	assert.Equal(t, `"42"`, string(lo.Must(node.GetByPath("inputs", "arg2").MarshalJSON())))
+
+	node, idx, err = DecodeLogVariants([][32]byte{erc721.ID, from, from, value}, nil, events)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, idx)
+	assert.Equal(t, `"42"`, string(lo.Must(node.GetByPath("inputs", "arg2").MarshalJSON())))
+
+	_, idx, err = DecodeLogVariants([][32]byte{erc721.ID, from, from, value}, value[:], events)
+	assert.ErrorIs(t, err, ErrNoMatchingEvent)
+	assert.Equal(t, -1, idx)
+}
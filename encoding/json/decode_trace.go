@@ -2,30 +2,39 @@ package json
 
 import (
 	"fmt"
+	"slices"
 
+	"github.com/agnosticeng/evmabi/encoding"
 	"github.com/bytedance/sonic/ast"
 	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
 )
 
-func DecodeTrace(input []byte, output []byte, method eth_abi.Method) (ast.Node, error) {
+func DecodeTrace(input []byte, output []byte, method eth_abi.Method, opts ...encoding.DecodeOption) (ast.Node, error) {
+	// the limits apply to the whole call, inputs and outputs
+	opts = append(slices.Clip(opts), encoding.WithSharedLimits())
+
 	if len(input) < 4 {
 		return ast.Node{}, fmt.Errorf("trace input is smaller than 4 bytes")
 	}
 
+	if err := checkID("selector", method.ID, input[:4], opts); err != nil {
+		return ast.Node{}, err
+	}
+
 	if len(method.Outputs) == 0 && len(output) > 0 {
 		return ast.Node{}, fmt.Errorf("trace has output data but method has no outputs")
 	}
 
-	inputs, err := DecodeArguments(input[4:], method.Inputs)
+	inputs, err := DecodeArguments(input[4:], method.Inputs, opts...)
 
 	if err != nil {
-		return ast.Node{}, err
+		return ast.Node{}, prependPath(err, "inputs")
 	}
 
-	outputs, err := DecodeArguments(output, method.Outputs)
+	outputs, err := DecodeArguments(output, method.Outputs, opts...)
 
 	if err != nil {
-		return ast.Node{}, err
+		return ast.Node{}, prependPath(err, "outputs")
 	}
 
 	return ast.NewObject([]ast.Pair{
@@ -34,3 +43,43 @@ func DecodeTrace(input []byte, output []byte, method eth_abi.Method) (ast.Node,
 		ast.NewPair("outputs", outputs),
 	}), nil
 }
+
+func DecodeTraceWithStatus(input []byte, output []byte, reverted bool, method eth_abi.Method, errors []eth_abi.Error, opts ...encoding.DecodeOption) (ast.Node, error) {
+	if !reverted {
+		return DecodeTrace(input, output, method, opts...)
+	}
+
+	// the limits apply to the whole call, inputs and revert data
+	opts = append(slices.Clip(opts), encoding.WithSharedLimits())
+
+	if len(input) < 4 {
+		return ast.Node{}, fmt.Errorf("trace input is smaller than 4 bytes")
+	}
+
+	if err := checkID("selector", method.ID, input[:4], opts); err != nil {
+		return ast.Node{}, err
+	}
+
+	inputs, err := DecodeArguments(input[4:], method.Inputs, opts...)
+
+	if err != nil {
+		return ast.Node{}, prependPath(err, "inputs")
+	}
+
+	// a revert without reason (e.g. require without message, out of gas) has no output data
+	var revert = ast.NewNull()
+
+	if len(output) > 0 {
+		revert, err = DecodeRevert(output, errors, opts...)
+
+		if err != nil {
+			return ast.Node{}, fmt.Errorf("failed to decode revert data: %w", prependPath(err, "error"))
+		}
+	}
+
+	return ast.NewObject([]ast.Pair{
+		ast.NewPair("signature", ast.NewString(method.Sig)),
+		ast.NewPair("inputs", inputs),
+		ast.NewPair("error", revert),
+	}), nil
+}
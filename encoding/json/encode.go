@@ -0,0 +1,399 @@
+package json
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/agnosticeng/evmabi/abi"
+	"github.com/agnosticeng/evmabi/encoding"
+	"github.com/bytedance/sonic/ast"
+	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func EncodeArguments(node ast.Node, args eth_abi.Arguments) ([]byte, error) {
+	return encoding.EncodeArguments(func(yield func(*encoding.Event, error) bool) {
+		var err = WriteArguments(&node, args, yield)
+
+		if err == nil || errors.Is(err, encoding.ErrIterStop) {
+			return
+		}
+
+		yield(nil, err)
+	}, args)
+}
+
+func EncodeValue(node ast.Node, t eth_abi.Type) ([]byte, error) {
+	return encoding.EncodeValue(func(yield func(*encoding.Event, error) bool) {
+		var err = WriteValue(&node, t, yield)
+
+		if err == nil || errors.Is(err, encoding.ErrIterStop) {
+			return
+		}
+
+		yield(nil, err)
+	}, t)
+}
+
+func EncodeTopic(node ast.Node, t eth_abi.Type) ([32]byte, error) {
+	return encoding.EncodeTopic(func(yield func(*encoding.Event, error) bool) {
+		var err = WriteTopic(&node, t, yield)
+
+		if err == nil || errors.Is(err, encoding.ErrIterStop) {
+			return
+		}
+
+		yield(nil, err)
+	}, t)
+}
+
+// WriteTopic is like WriteValue but also accepts the {"hash": "0x..."} representation
+// of indexed reference types, as produced by DecodeLog
+func WriteTopic(node *ast.Node, t eth_abi.Type, fn encoding.YieldFunc) error {
+	node, err := resolveNode(node)
+
+	if err != nil {
+		return err
+	}
+
+	if !isTopicHash(node, t) {
+		return WriteValue(node, t, fn)
+	}
+
+	b, err := readHex(node.Get("hash"), t)
+
+	if err != nil {
+		return err
+	}
+
+	if len(b) != common.HashLength {
+		return fmt.Errorf("invalid hash length: %d", len(b))
+	}
+
+	return encoding.Yield(fn, &encoding.Event{
+		Type:    encoding.Value,
+		ABIType: t,
+		Value:   encoding.TopicHash(common.BytesToHash(b)),
+	})
+}
+
+func WriteArguments(node *ast.Node, args eth_abi.Arguments, fn encoding.YieldFunc) error {
+	node, err := resolveNode(node)
+
+	if err != nil {
+		return err
+	}
+
+	if node.TypeSafe() != ast.V_OBJECT {
+		return fmt.Errorf("wanted JSON object for arguments")
+	}
+
+	if err := encoding.Yield(fn, &encoding.Event{
+		Type: encoding.TupleStart,
+		Len:  len(args),
+	}); err != nil {
+		return err
+	}
+
+	for i, arg := range args {
+		if err := encoding.Yield(fn, &encoding.Event{
+			Type:  encoding.Key,
+			Key:   arg.Name,
+			Index: i,
+		}); err != nil {
+			return err
+		}
+
+		var v = node.Get(arg.Name)
+
+		if !v.Exists() {
+			return fmt.Errorf("missing argument %s", arg.Name)
+		}
+
+		if err := WriteValue(v, arg.Type, fn); err != nil {
+			return err
+		}
+	}
+
+	return encoding.Yield(fn, &encoding.Event{
+		Type: encoding.TupleEnd,
+	})
+}
+
+func WriteTuple(node *ast.Node, t eth_abi.Type, fn encoding.YieldFunc) error {
+	if node.TypeSafe() != ast.V_OBJECT {
+		return fmt.Errorf("wanted JSON object for %s", t.String())
+	}
+
+	if err := encoding.Yield(fn, &encoding.Event{
+		Type:    encoding.TupleStart,
+		ABIType: t,
+		Len:     len(t.TupleElems),
+	}); err != nil {
+		return err
+	}
+
+	for i, elem := range t.TupleElems {
+		if err := encoding.Yield(fn, &encoding.Event{
+			Type:  encoding.Key,
+			Key:   t.TupleRawNames[i],
+			Index: i,
+		}); err != nil {
+			return err
+		}
+
+		var v = node.Get(t.TupleRawNames[i])
+
+		if !v.Exists() {
+			return fmt.Errorf("missing tuple field %s", t.TupleRawNames[i])
+		}
+
+		if err := WriteValue(v, *elem, fn); err != nil {
+			return err
+		}
+	}
+
+	return encoding.Yield(fn, &encoding.Event{
+		Type: encoding.TupleEnd,
+	})
+}
+
+func WriteArray(node *ast.Node, t eth_abi.Type, fn encoding.YieldFunc) error {
+	if node.TypeSafe() != ast.V_ARRAY {
+		return fmt.Errorf("wanted JSON array for %s", t.String())
+	}
+
+	elems, err := node.ArrayUseNode()
+
+	if err != nil {
+		return err
+	}
+
+	if err := encoding.Yield(fn, &encoding.Event{
+		Type:    encoding.ArrayStart,
+		ABIType: t,
+		Len:     len(elems),
+	}); err != nil {
+		return err
+	}
+
+	for _, elem := range elems {
+		if err := WriteValue(&elem, *t.Elem, fn); err != nil {
+			return err
+		}
+	}
+
+	return encoding.Yield(fn, &encoding.Event{
+		Type: encoding.ArrayEnd,
+	})
+}
+
+func WriteValue(node *ast.Node, t eth_abi.Type, fn encoding.YieldFunc) error {
+	node, err := resolveNode(node)
+
+	if err != nil {
+		return err
+	}
+
+	switch t.T {
+	case eth_abi.TupleTy:
+		return WriteTuple(node, t, fn)
+
+	case eth_abi.ArrayTy, eth_abi.SliceTy:
+		return WriteArray(node, t, fn)
+	}
+
+	v, err := readScalar(node, t)
+
+	if err != nil {
+		return err
+	}
+
+	return encoding.Yield(fn, &encoding.Event{
+		Type:    encoding.Value,
+		ABIType: t,
+		Value:   v,
+	})
+}
+
+func readScalar(node *ast.Node, t eth_abi.Type) (interface{}, error) {
+	switch t.T {
+	case eth_abi.UintTy, eth_abi.IntTy:
+		var s string
+
+		switch node.TypeSafe() {
+		case ast.V_STRING:
+			s, _ = node.StrictString()
+		case ast.V_NUMBER:
+			n, _ := node.StrictNumber()
+			s = n.String()
+		default:
+			return nil, fmt.Errorf("wanted JSON string or number for %s", t.String())
+		}
+
+		i, ok := new(big.Int).SetString(s, 10)
+
+		if !ok {
+			return nil, fmt.Errorf("invalid integer for %s: %s", t.String(), s)
+		}
+
+		if t.T == eth_abi.UintTy && i.Sign() == -1 {
+			return nil, fmt.Errorf("negative integer for %s: %s", t.String(), s)
+		}
+
+		return i, nil
+
+	case eth_abi.FixedPointTy:
+		var s string
+
+		switch node.TypeSafe() {
+		case ast.V_STRING:
+			s, _ = node.StrictString()
+		case ast.V_NUMBER:
+			n, _ := node.StrictNumber()
+			s = n.String()
+		default:
+			return nil, fmt.Errorf("wanted JSON string or number for %s", t.String())
+		}
+
+		_, _, decimals, err := abi.FixedPointParams(t)
+
+		if err != nil {
+			return nil, err
+		}
+
+		d, err := encoding.ParseDecimal(s, decimals)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid decimal for %s: %w", t.String(), err)
+		}
+
+		return d, nil
+
+	case eth_abi.BoolTy:
+		switch node.TypeSafe() {
+		case ast.V_TRUE:
+			return true, nil
+		case ast.V_FALSE:
+			return false, nil
+		default:
+			return nil, fmt.Errorf("wanted JSON boolean for %s", t.String())
+		}
+
+	case eth_abi.AddressTy:
+		s, err := node.StrictString()
+
+		if err != nil {
+			return nil, fmt.Errorf("wanted JSON string for %s", t.String())
+		}
+
+		if !common.IsHexAddress(s) {
+			return nil, fmt.Errorf("invalid address: %s", s)
+		}
+
+		return common.HexToAddress(s), nil
+
+	case eth_abi.HashTy:
+		b, err := readHex(node, t)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(b) != common.HashLength {
+			return nil, fmt.Errorf("invalid hash length: %d", len(b))
+		}
+
+		return common.BytesToHash(b), nil
+
+	case eth_abi.StringTy:
+		s, err := node.StrictString()
+
+		if err != nil {
+			return nil, fmt.Errorf("wanted JSON string for %s", t.String())
+		}
+
+		return s, nil
+
+	case eth_abi.BytesTy, eth_abi.FixedBytesTy, eth_abi.FunctionTy:
+		return readHex(node, t)
+
+	default:
+		return nil, fmt.Errorf("abi: unknown type %v", t.T)
+	}
+}
+
+func readHex(node *ast.Node, t eth_abi.Type) ([]byte, error) {
+	s, err := node.StrictString()
+
+	if err != nil {
+		return nil, fmt.Errorf("wanted JSON string for %s", t.String())
+	}
+
+	b, err := hexutil.Decode(s)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex for %s: %w", t.String(), err)
+	}
+
+	return b, nil
+}
+
+// resolveNode turns V_ANY nodes (as built by the decoder) into plain JSON nodes
+func resolveNode(node *ast.Node) (*ast.Node, error) {
+	if err := node.Check(); err != nil {
+		return nil, err
+	}
+
+	if node.TypeSafe() != ast.V_ANY {
+		return node, nil
+	}
+
+	js, err := node.MarshalJSON()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var res = ast.NewRaw(string(js))
+
+	if err := res.Check(); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func isTopicHash(node *ast.Node, t eth_abi.Type) bool {
+	switch t.T {
+	case eth_abi.StringTy, eth_abi.BytesTy, eth_abi.SliceTy, eth_abi.ArrayTy:
+	case eth_abi.TupleTy:
+		// a tuple with a single "hash" field is encoded from its value
+		if len(t.TupleRawNames) == 1 && t.TupleRawNames[0] == "hash" {
+			return false
+		}
+	default:
+		return false
+	}
+
+	if node.TypeSafe() != ast.V_OBJECT {
+		return false
+	}
+
+	m, err := node.Interface()
+
+	if err != nil {
+		return false
+	}
+
+	obj, ok := m.(map[string]interface{})
+
+	if !ok || len(obj) != 1 {
+		return false
+	}
+
+	_, ok = obj["hash"].(string)
+	return ok
+}
@@ -0,0 +1,36 @@
+package json
+
+import (
+	"fmt"
+
+	"github.com/bytedance/sonic/ast"
+	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+func EncodeCallData(node ast.Node, method eth_abi.Method) ([]byte, error) {
+	if sig := node.Get("signature"); sig.Exists() {
+		s, err := sig.StrictString()
+
+		if err != nil {
+			return nil, fmt.Errorf("signature must be a string")
+		}
+
+		if s != method.Sig {
+			return nil, fmt.Errorf("signature mismatch; wanted %s but got %s", method.Sig, s)
+		}
+	}
+
+	var inputs = node.Get("inputs")
+
+	if !inputs.Exists() {
+		return nil, fmt.Errorf("call data has no inputs")
+	}
+
+	data, err := EncodeArguments(*inputs, method.Inputs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append([]byte{}, method.ID...), data...), nil
+}
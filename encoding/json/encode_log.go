@@ -0,0 +1,47 @@
+package json
+
+import (
+	"fmt"
+
+	"github.com/bytedance/sonic/ast"
+	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+func EncodeLog(inputs ast.Node, event eth_abi.Event) ([][32]byte, []byte, error) {
+	var (
+		indexed, unindexed = SplitInputs(event.Inputs)
+		topics             [][32]byte
+	)
+
+	if !event.Anonymous {
+		topics = append(topics, event.ID)
+	}
+
+	if len(indexed)+len(topics) > 4 {
+		return nil, nil, fmt.Errorf("event has too many indexed inputs: %d", len(indexed))
+	}
+
+	for _, input := range indexed {
+		var v = inputs.Get(input.Name)
+
+		if !v.Exists() {
+			return nil, nil, fmt.Errorf("missing indexed input %s", input.Name)
+		}
+
+		topic, err := EncodeTopic(*v, input.Type)
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode indexed field %s: %w", input.Name, err)
+		}
+
+		topics = append(topics, topic)
+	}
+
+	data, err := EncodeArguments(inputs, unindexed)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode non-indexed fields: %w", err)
+	}
+
+	return topics, data, nil
+}
@@ -0,0 +1,112 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/agnosticeng/evmabi/fullsig"
+	"github.com/bytedance/sonic/ast"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/swaggest/assertjson"
+)
+
+func TestEncodeCallData(t *testing.T) {
+	for _, trace := range traceTestData {
+		t.Run(trace.MethodName, func(t *testing.T) {
+			var (
+				method = _abi.Methods[trace.MethodName]
+				input  = hexutil.MustDecode(trace.Input)
+				node   = ast.NewRaw(string(trace.Result))
+			)
+
+			res, err := EncodeCallData(node, method)
+			assert.NoError(t, err)
+			assert.Equal(t, hexutil.Encode(input), hexutil.Encode(res))
+
+			decoded, err := DecodeCallData(input, method)
+			assert.NoError(t, err)
+			res, err = EncodeCallData(decoded, method)
+			assert.NoError(t, err)
+			assert.Equal(t, hexutil.Encode(input), hexutil.Encode(res))
+		})
+	}
+}
+
+func TestEncodeLog(t *testing.T) {
+	for _, log := range logTestData {
+		t.Run(log.EventName, func(t *testing.T) {
+			var (
+				event  = _abi.Events[log.EventName]
+				node   = ast.NewRaw(string(log.Result))
+				topics = lo.Map(log.Topics, func(topic string, _ int) [32]byte { return [32]byte(hexutil.MustDecode(topic)) })
+			)
+
+			resTopics, resData, err := EncodeLog(*node.Get("inputs"), event)
+			assert.NoError(t, err)
+			assert.Equal(t, topics, resTopics)
+			assert.Equal(t, log.Input, hexutil.Encode(resData))
+		})
+	}
+}
+
+func TestEncodeLogIndexedDynamic(t *testing.T) {
+	var event = lo.Must(fullsig.ParseEvent("event E(string indexed,uint256[] indexed,(string,uint256) indexed,uint256)"))
+
+	topics, data, err := EncodeLog(ast.NewRaw(`{"arg0":"hello","arg1":["1","2"],"arg2":{"arg0":"hello","arg1":"3"},"arg3":"4"}`), event)
+	assert.NoError(t, err)
+	assert.Equal(t, [][32]byte{
+		event.ID,
+		crypto.Keccak256Hash([]byte("hello")),
+		crypto.Keccak256Hash(common.LeftPadBytes([]byte{1}, 32), common.LeftPadBytes([]byte{2}, 32)),
+		crypto.Keccak256Hash(common.RightPadBytes([]byte("hello"), 32), common.LeftPadBytes([]byte{3}, 32)),
+	}, topics)
+	assert.Equal(t, common.LeftPadBytes([]byte{4}, 32), data)
+
+	decoded, err := DecodeLog(topics, data, event)
+	assert.NoError(t, err)
+	js, err := decoded.Get("inputs").MarshalJSON()
+	assert.NoError(t, err)
+	assertjson.Equal(t, []byte(`{
+		"arg0": {"hash": "`+common.Hash(topics[1]).Hex()+`"},
+		"arg1": {"hash": "`+common.Hash(topics[2]).Hex()+`"},
+		"arg2": {"hash": "`+common.Hash(topics[3]).Hex()+`"},
+		"arg3": "4"
+	}`), js)
+
+	resTopics, resData, err := EncodeLog(*decoded.Get("inputs"), event)
+	assert.NoError(t, err)
+	assert.Equal(t, topics, resTopics)
+	assert.Equal(t, data, resData)
+
+	resTopics, _, err = EncodeLog(ast.NewRaw(string(js)), event)
+	assert.NoError(t, err)
+	assert.Equal(t, topics, resTopics)
+}
+
+func TestEncodeFixedPoint(t *testing.T) {
+	var (
+		method = lo.Must(fullsig.ParseMethod("function f(fixed128x18,ufixed8x1[],(fixed,int8))"))
+		node   = ast.NewRaw(`{"inputs":{"arg0":"-1.5","arg1":["25.5","0.1"],"arg2":{"arg0":"0.000000000000000001","arg1":"-1"}}}`)
+	)
+
+	assert.Equal(t, "f(fixed128x18,ufixed8x1[],(fixed128x18,int8))", method.Sig)
+
+	res, err := EncodeCallData(node, method)
+	assert.NoError(t, err)
+	assert.Equal(t, byte(0xff), res[4])
+
+	decoded, err := DecodeCallData(res, method)
+	assert.NoError(t, err)
+	js, err := decoded.Get("inputs").MarshalJSON()
+	assert.NoError(t, err)
+	assertjson.Equal(t, []byte(`{"arg0":"-1.5","arg1":["25.5","0.1"],"arg2":{"arg0":"0.000000000000000001","arg1":"-1"}}`), js)
+
+	_, err = EncodeCallData(ast.NewRaw(`{"inputs":{"arg0":"1.5","arg1":["25.6"],"arg2":{"arg0":"0","arg1":"0"}}}`), method)
+	assert.Error(t, err)
+
+	_, err = EncodeCallData(ast.NewRaw(`{"inputs":{"arg0":"1.5","arg1":["0.01"],"arg2":{"arg0":"0","arg1":"0"}}}`), method)
+	assert.Error(t, err)
+}
@@ -8,15 +8,19 @@ import (
 	"github.com/agnosticeng/evmabi/encoding"
 	"github.com/bytedance/sonic/ast"
 	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/holiman/uint256"
 )
 
-var ErrEndOfSeq = errors.New("end of seq")
+var (
+	ErrEndOfSeq        = errors.New("end of seq")
+	ErrNoMatchingEvent = errors.New("no matching event")
+)
 
-func DecodeArguments(data []byte, args eth_abi.Arguments) (ast.Node, error) {
+func DecodeArguments(data []byte, args eth_abi.Arguments, opts ...encoding.DecodeOption) (ast.Node, error) {
 	var (
-		it         = encoding.DecodeArguments(data, args)
+		it         = encoding.DecodeArguments(data, args, opts...)
 		next, stop = iter.Pull2[*encoding.Event, error](it)
 	)
 
@@ -24,9 +28,19 @@ func DecodeArguments(data []byte, args eth_abi.Arguments) (ast.Node, error) {
 	return ReadValue(next)
 }
 
-func DecodeValue(data []byte, t eth_abi.Type) (ast.Node, error) {
+func DecodeValue(data []byte, t eth_abi.Type, opts ...encoding.DecodeOption) (ast.Node, error) {
 	var (
-		it         = encoding.DecodeValue(data, t)
+		it         = encoding.DecodeValue(data, t, opts...)
+		next, stop = iter.Pull2[*encoding.Event, error](it)
+	)
+
+	defer stop()
+	return ReadValue(next)
+}
+
+func DecodeTopic(topic []byte, t eth_abi.Type, opts ...encoding.DecodeOption) (ast.Node, error) {
+	var (
+		it         = encoding.DecodeTopic(topic, t, opts...)
 		next, stop = iter.Pull2[*encoding.Event, error](it)
 	)
 
@@ -116,12 +130,28 @@ func ReadValue(next func() (*encoding.Event, error, bool)) (ast.Node, error) {
 
 	switch evt.Type {
 	case encoding.Value:
+		if h, ok := evt.Value.(encoding.TopicHash); ok {
+			return ast.NewObject([]ast.Pair{
+				ast.NewPair("hash", ast.NewString(common.Hash(h).Hex())),
+			}), nil
+		}
+
 		switch evt.ABIType.T {
-		case eth_abi.BytesTy, eth_abi.FixedBytesTy:
-			return ast.NewAny(hexutil.Bytes(evt.Value.([]byte))), nil
+		case eth_abi.BytesTy, eth_abi.FixedBytesTy, eth_abi.FunctionTy:
+			b, ok := evt.Value.([]byte)
+
+			if !ok {
+				return ast.Node{}, fmt.Errorf("wrong value type for %s: %T", evt.ABIType.String(), evt.Value)
+			}
+
+			return ast.NewAny(hexutil.Bytes(b)), nil
 
 		case eth_abi.IntTy:
-			var i = evt.Value.(*uint256.Int)
+			i, ok := evt.Value.(*uint256.Int)
+
+			if !ok {
+				return ast.Node{}, fmt.Errorf("wrong value type for %s: %T", evt.ABIType.String(), evt.Value)
+			}
 
 			if i.Sign() == -1 {
 				return ast.NewAny(fmt.Sprintf("-%d", uint256.NewInt(0).Neg(i))), nil
@@ -141,6 +171,17 @@ func ReadValue(next func() (*encoding.Event, error, bool)) (ast.Node, error) {
 	}
 }
 
+// prependPath prefixes the value path of a decode error, if any, with the given prefix
+func prependPath(err error, prefix string) error {
+	var decodeErr *encoding.DecodeError
+
+	if errors.As(err, &decodeErr) {
+		decodeErr.PrependPath(prefix)
+	}
+
+	return err
+}
+
 func pullEvent(next func() (*encoding.Event, error, bool)) (*encoding.Event, error) {
 	var evt, err, ok = next()
 
@@ -0,0 +1,220 @@
+package encoding
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+type DecodeOptions struct {
+	// Strict rejects any input that is not the canonical encoding of the decoded values:
+	// non-zero padding, non sign-extended ints, out-of-order or overlapping tails and trailing bytes.
+	// Inputs are re-encoded and compared, so strict decoding costs roughly one more decode and one encode.
+	Strict bool
+
+	Limits Limits
+
+	// SkipIDCheck disables the verification of the call data selector and of the log topic0
+	// against the ABI fragment, e.g. to intentionally decode raw data with an unrelated fragment.
+	SkipIDCheck bool
+
+	// usage is the budget counted against Limits, shared by all the decodes using the same WithSharedLimits option
+	usage *limitUsage
+}
+
+// Limits bounds the work done on untrusted inputs; a zero value means no limit.
+// MaxValues and MaxBytes apply to a single decode, or to all the decodes sharing a WithSharedLimits option.
+type Limits struct {
+	// MaxDepth is the maximum nesting of tuples and arrays
+	MaxDepth int
+
+	// MaxValues is the maximum number of values (scalars, tuples and arrays) emitted
+	MaxValues int
+
+	// MaxArrayLength is the maximum length of a single array
+	MaxArrayLength int
+
+	// MaxBytes is the maximum total length of emitted strings and byte slices
+	MaxBytes int
+}
+
+type DecodeOption func(*DecodeOptions)
+
+func WithStrict(strict bool) DecodeOption {
+	return func(opts *DecodeOptions) {
+		opts.Strict = strict
+	}
+}
+
+func WithLimits(limits Limits) DecodeOption {
+	return func(opts *DecodeOptions) {
+		opts.Limits = limits
+	}
+}
+
+func WithIDCheck(check bool) DecodeOption {
+	return func(opts *DecodeOptions) {
+		opts.SkipIDCheck = !check
+	}
+}
+
+// WithSharedLimits makes all the decodes using this option count their values and bytes against the same Limits,
+// e.g. the topics and data of a log, or the inputs and outputs of a call. An already shared budget is kept.
+func WithSharedLimits() DecodeOption {
+	var usage limitUsage
+
+	return func(opts *DecodeOptions) {
+		if opts.usage == nil {
+			opts.usage = &usage
+		}
+	}
+}
+
+func NewDecodeOptions(opts ...DecodeOption) DecodeOptions {
+	var res DecodeOptions
+
+	for _, opt := range opts {
+		opt(&res)
+	}
+
+	return res
+}
+
+type decodeState struct {
+	opts DecodeOptions
+
+	// raw disables value transformations (e.g. quoting of invalid UTF-8 strings)
+	// so that the emitted events can be re-encoded to the original bytes
+	raw bool
+
+	depth int
+	usage *limitUsage
+
+	// base is the offset of the current data slice in the original input
+	base int
+	path []pathSegment
+}
+
+type limitUsage struct {
+	values int
+	bytes  int
+}
+
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+func newDecodeState(opts DecodeOptions) *decodeState {
+	var res = decodeState{opts: opts, usage: opts.usage}
+
+	if res.usage == nil {
+		res.usage = &limitUsage{}
+	}
+
+	return &res
+}
+
+// newCheckState returns a state for the strict mode check pass, which must not spend the shared budget twice
+func newCheckState(opts DecodeOptions) *decodeState {
+	var res = newDecodeState(opts)
+
+	res.usage = &limitUsage{values: res.usage.values, bytes: res.usage.bytes}
+	return res
+}
+
+func (st *decodeState) enter() error {
+	st.depth++
+
+	if st.opts.Limits.MaxDepth > 0 && st.depth > st.opts.Limits.MaxDepth {
+		return fmt.Errorf("%w: depth greater than %d", ErrLimitExceeded, st.opts.Limits.MaxDepth)
+	}
+
+	return nil
+}
+
+func (st *decodeState) leave() {
+	st.depth--
+}
+
+func (st *decodeState) countValue() error {
+	st.usage.values++
+
+	if st.opts.Limits.MaxValues > 0 && st.usage.values > st.opts.Limits.MaxValues {
+		return fmt.Errorf("%w: more than %d values", ErrLimitExceeded, st.opts.Limits.MaxValues)
+	}
+
+	return nil
+}
+
+func (st *decodeState) countBytes(n int) error {
+	st.usage.bytes += n
+
+	if st.opts.Limits.MaxBytes > 0 && st.usage.bytes > st.opts.Limits.MaxBytes {
+		return fmt.Errorf("%w: more than %d bytes", ErrLimitExceeded, st.opts.Limits.MaxBytes)
+	}
+
+	return nil
+}
+
+func (st *decodeState) checkArrayLength(n int) error {
+	if st.opts.Limits.MaxArrayLength > 0 && n > st.opts.Limits.MaxArrayLength {
+		return fmt.Errorf("%w: array length %d greater than %d", ErrLimitExceeded, n, st.opts.Limits.MaxArrayLength)
+	}
+
+	return nil
+}
+
+func (st *decodeState) pushKey(key string) {
+	st.path = append(st.path, pathSegment{key: key})
+}
+
+func (st *decodeState) pushIndex(index int) {
+	st.path = append(st.path, pathSegment{index: index, isIndex: true})
+}
+
+func (st *decodeState) pop() {
+	st.path = st.path[:len(st.path)-1]
+}
+
+func (st *decodeState) pathString() string {
+	var sb strings.Builder
+
+	for _, seg := range st.path {
+		if seg.isIndex {
+			sb.WriteString("[" + strconv.Itoa(seg.index) + "]")
+			continue
+		}
+
+		if sb.Len() > 0 {
+			sb.WriteString(".")
+		}
+
+		sb.WriteString(seg.key)
+	}
+
+	return sb.String()
+}
+
+func (st *decodeState) wrapError(err error, idx int, t eth_abi.Type) error {
+	if err == nil || errors.Is(err, ErrIterStop) {
+		return err
+	}
+
+	var decodeErr *DecodeError
+
+	if errors.As(err, &decodeErr) {
+		return err
+	}
+
+	return &DecodeError{
+		Offset: st.base + idx,
+		Type:   t,
+		Path:   st.pathString(),
+		Err:    err,
+	}
+}
@@ -7,7 +7,6 @@ import (
 
 	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/holiman/uint256"
-	"github.com/samber/lo"
 )
 
 func geteDataSlice(data []byte, idx int, t eth_abi.Type) ([]byte, error) {
@@ -31,7 +30,7 @@ func decodeLengthPrefix(data []byte, idx int) (int, int, error) {
 		return 0, 0, fmt.Errorf("offset larger than uint64")
 	}
 
-	if (offset + 32) > uint64(len(data)) {
+	if offset > uint64(len(data)) || uint64(len(data))-offset < 32 {
 		return 0, 0, fmt.Errorf("offset points over data slice boundary")
 	}
 
@@ -41,7 +40,7 @@ func decodeLengthPrefix(data []byte, idx int) (int, int, error) {
 		return 0, 0, fmt.Errorf("length larger than uint64")
 	}
 
-	if (offset + 32 + length) > uint64(len(data)) {
+	if length > uint64(len(data))-offset-32 {
 		return 0, 0, fmt.Errorf("offset+length points over data slice boundary")
 	}
 
@@ -52,41 +51,150 @@ func isLengthPrefixed(t eth_abi.Type) bool {
 	return t.T == eth_abi.StringTy || t.T == eth_abi.BytesTy || t.T == eth_abi.SliceTy
 }
 
-func isDynamic(t eth_abi.Type) bool {
+func isDynamic(t eth_abi.Type) (bool, error) {
 	switch t.T {
-	case eth_abi.StringTy, eth_abi.BytesTy, eth_abi.SliceTy:
-		return true
+	case eth_abi.StringTy, eth_abi.BytesTy:
+		return true, nil
+
+	case eth_abi.SliceTy:
+		if t.Elem == nil {
+			return false, fmt.Errorf("slice type has no element type")
+		}
+
+		return true, nil
 
 	case eth_abi.TupleTy:
+		if len(t.TupleElems) != len(t.TupleRawNames) {
+			return false, fmt.Errorf("tuple type has %d elements but %d names", len(t.TupleElems), len(t.TupleRawNames))
+		}
+
+		var res = false
+
 		for _, elem := range t.TupleElems {
-			if isDynamic(*elem) {
-				return true
+			if elem == nil {
+				return false, fmt.Errorf("tuple type has a nil element type")
 			}
+
+			dynamic, err := isDynamic(*elem)
+
+			if err != nil {
+				return false, err
+			}
+
+			res = res || dynamic
 		}
 
-		return false
+		return res, nil
 
 	case eth_abi.ArrayTy:
+		if t.Elem == nil {
+			return false, fmt.Errorf("array type has no element type")
+		}
+
 		return isDynamic(*t.Elem)
 
-	case eth_abi.HashTy, eth_abi.AddressTy, eth_abi.BoolTy, eth_abi.IntTy, eth_abi.UintTy, eth_abi.FixedBytesTy:
-		return false
+	case eth_abi.HashTy, eth_abi.AddressTy, eth_abi.BoolTy, eth_abi.IntTy, eth_abi.UintTy, eth_abi.FixedBytesTy, eth_abi.FunctionTy, eth_abi.FixedPointTy:
+		return false, nil
 
 	default:
-		panic(fmt.Sprintf("cannot determine if this type is dynamic: %v", t.String()))
+		return false, fmt.Errorf("cannot determine if this type is dynamic: %v", t.T)
 	}
 }
 
-func typeSize(t eth_abi.Type) int {
+// isHashedTopic reports whether an indexed argument of this type is stored as the keccak256 hash of its value
+func isHashedTopic(t eth_abi.Type) bool {
+	switch t.T {
+	case eth_abi.StringTy, eth_abi.BytesTy, eth_abi.SliceTy, eth_abi.ArrayTy, eth_abi.TupleTy:
+		return true
+
+	default:
+		return false
+	}
+}
+
+// checkArgumentTypes validates argument types up front, so that malformed types are reported as errors
+func checkArgumentTypes(args eth_abi.Arguments) error {
+	for _, arg := range args {
+		if _, err := isDynamic(arg.Type); err != nil {
+			return fmt.Errorf("argument %s: %w", arg.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// HeadSize returns the size of the head of encoded arguments, and whether any of them is dynamic,
+// in which case the encoding is longer than its head
+func HeadSize(args eth_abi.Arguments) (int, bool, error) {
+	var (
+		size    int
+		dynamic bool
+	)
+
+	for _, arg := range args {
+		d, err := isDynamic(arg.Type)
+
+		if err != nil {
+			return 0, false, fmt.Errorf("argument %s: %w", arg.Name, err)
+		}
+
+		s, err := typeSize(arg.Type)
+
+		if err != nil {
+			return 0, false, fmt.Errorf("argument %s: %w", arg.Name, err)
+		}
+
+		size += s
+		dynamic = dynamic || d
+	}
+
+	return size, dynamic, nil
+}
+
+// argumentSize returns the size of the head of an argument; indexed reference type arguments hold their topic hash
+func argumentSize(arg eth_abi.Argument) (int, error) {
+	if arg.Indexed && isHashedTopic(arg.Type) {
+		return 32, nil
+	}
+
+	return typeSize(arg.Type)
+}
+
+// typeSize returns the size of the head of a value: static arrays and tuples are encoded in place
+func typeSize(t eth_abi.Type) (int, error) {
+	dynamic, err := isDynamic(t)
+
+	if err != nil {
+		return 0, err
+	}
+
 	switch {
-	case t.T == eth_abi.ArrayTy && !isDynamic(t):
-		return t.Size * typeSize(*t.Elem)
+	case t.T == eth_abi.ArrayTy && !dynamic:
+		elemSize, err := typeSize(*t.Elem)
+
+		if err != nil {
+			return 0, err
+		}
+
+		return t.Size * elemSize, nil
+
+	case t.T == eth_abi.TupleTy && !dynamic:
+		var res = 0
+
+		for _, elem := range t.TupleElems {
+			elemSize, err := typeSize(*elem)
+
+			if err != nil {
+				return 0, err
+			}
+
+			res += elemSize
+		}
 
-	case t.T == eth_abi.TupleTy && isDynamic(t):
-		return lo.SumBy(t.TupleElems, func(t *eth_abi.Type) int { return typeSize(*t) })
+		return res, nil
 
 	default:
-		return 32
+		return 32, nil
 	}
 }
 
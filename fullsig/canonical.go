@@ -0,0 +1,100 @@
+package fullsig
+
+import (
+	"errors"
+	"fmt"
+
+	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Canonicalize normalises a human-typed signature into the canonical form used for hashing:
+// "function transfer(address to, uint amount) external returns (bool)" gives "transfer(address,uint256)".
+// Keywords, names, modifiers, indexed flags and outputs are dropped, type aliases are expanded and tuples are
+// rendered as "(...)". Signatures may be any fragment fullsig, or a bare "name(types)" without keyword;
+// constructor, fallback and receive fragments have no canonical form.
+func Canonicalize(sig string) (string, error) {
+	f, err := parseSignature(sig)
+
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case f.Event != nil:
+		return f.Event.Sig, nil
+	case f.Error != nil:
+		return f.Error.Sig, nil
+	case f.Method.Type == eth_abi.Function:
+		return f.Method.Sig, nil
+	default:
+		return "", fmt.Errorf("%s has no canonical signature", methodKind(f.Method.Type))
+	}
+}
+
+// Selector returns the function or error selector of a signature, i.e. the first 4 bytes of its Topic
+func Selector(sig string) ([4]byte, error) {
+	topic, err := Topic(sig)
+
+	if err != nil {
+		return [4]byte{}, err
+	}
+
+	return [4]byte(topic[:4]), nil
+}
+
+// Topic returns the keccak256 hash of the canonical signature, which is the topic0 of events
+func Topic(sig string) (common.Hash, error) {
+	canonical, err := Canonicalize(sig)
+
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return crypto.Keccak256Hash([]byte(canonical)), nil
+}
+
+const bareSignaturePrefix = "function "
+
+// parseSignature parses a fragment fullsig, or a bare "name(types)" signature as a function
+func parseSignature(sig string) (Fragment, error) {
+	var stream = tknz.ParseString(sig)
+
+	var bare = stream.CurrentToken().IsKeyword() &&
+		stream.NextToken().Is(TokenOpenParens) &&
+		!isConstructorToken(stream.CurrentToken()) &&
+		!isFallbackToken(stream.CurrentToken()) &&
+		!isReceiveToken(stream.CurrentToken())
+
+	stream.Close()
+
+	if !bare {
+		return ParseFragment(sig)
+	}
+
+	f, err := ParseFragment(bareSignaturePrefix + sig)
+
+	// positions are reported relative to the signature as given
+	var pe *ParseError
+
+	if errors.As(err, &pe) {
+		pe.Offset = pe.Offset - len(bareSignaturePrefix)
+		pe.setSource(sig)
+	}
+
+	return f, err
+}
+
+func methodKind(t eth_abi.FunctionType) string {
+	switch t {
+	case eth_abi.Constructor:
+		return "constructor"
+	case eth_abi.Fallback:
+		return "fallback"
+	case eth_abi.Receive:
+		return "receive"
+	default:
+		return "function"
+	}
+}
@@ -0,0 +1,109 @@
+package fullsig
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bzick/tokenizer"
+)
+
+// ParseError is a syntax or type resolution error, located on the offending token
+type ParseError struct {
+	Source string
+
+	// Offset is the byte offset of the offending token in Source; Line and Column start at 1, Column counts runes
+	Offset int
+	Line   int
+	Column int
+
+	// Token is the offending token, empty at the end of the input
+	Token string
+	Msg   string
+}
+
+// Error omits the line for single-line sources, such as fullsigs
+func (e *ParseError) Error() string {
+	switch {
+	case e.Line == 0:
+		return e.Msg
+	case !strings.Contains(e.Source, "\n"):
+		return fmt.Sprintf("column %d: %s", e.Column, e.Msg)
+	default:
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+	}
+}
+
+// Caret renders the source line holding the offending token, with carets under the token:
+//
+//	function transfer(address to uint256 amount)
+//	                             ^^^^^^^
+func (e *ParseError) Caret() string {
+	var (
+		offset = min(max(e.Offset, 0), len(e.Source))
+		start  = strings.LastIndexByte(e.Source[:offset], '\n') + 1
+		end    = strings.IndexByte(e.Source[offset:], '\n')
+		pad    strings.Builder
+	)
+
+	if end == -1 {
+		end = len(e.Source)
+	} else {
+		end = end + offset
+	}
+
+	// tabs are kept so that the carets line up with the source
+	for _, r := range e.Source[start:offset] {
+		if r == '\t' {
+			pad.WriteRune('\t')
+		} else {
+			pad.WriteRune(' ')
+		}
+	}
+
+	return e.Source[start:end] + "\n" + pad.String() + strings.Repeat("^", max(utf8.RuneCountInString(e.Token), 1))
+}
+
+func (e *ParseError) setSource(s string) {
+	if e.Offset < 0 || e.Offset > len(s) {
+		e.Offset = len(s)
+	}
+
+	var start = strings.LastIndexByte(s[:e.Offset], '\n') + 1
+
+	e.Source = s
+	e.Line = 1 + strings.Count(s[:e.Offset], "\n")
+	e.Column = 1 + utf8.RuneCountInString(s[start:e.Offset])
+}
+
+// parseErrorf builds a ParseError on a token; its position is only known once positioned gives it the source
+func parseErrorf(tok *tokenizer.Token, format string, args ...any) *ParseError {
+	var res = ParseError{Offset: -1, Msg: fmt.Sprintf(format, args...)}
+
+	if tok.IsValid() {
+		res.Offset = tok.Offset()
+		res.Token = string(tok.Value())
+	}
+
+	return &res
+}
+
+// positioned sets the source of the ParseError held by err, if any
+func positioned(err error, s string) error {
+	var pe *ParseError
+
+	if errors.As(err, &pe) && len(pe.Source) == 0 {
+		pe.setSource(s)
+	}
+
+	return err
+}
+
+func tokenString(tok *tokenizer.Token) string {
+	if !tok.IsValid() {
+		return "EOF"
+	}
+
+	return string(tok.Value())
+}
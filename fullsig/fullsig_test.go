@@ -3,7 +3,9 @@ package fullsig
 import (
 	"bytes"
 	_ "embed"
+	"encoding/hex"
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -71,3 +73,306 @@ func TestStringify(t *testing.T) {
 		})
 	}
 }
+
+func TestParseNames(t *testing.T) {
+	var tests = []struct {
+		fullsig string
+		field   string
+	}{
+		{
+			"event Transfer(address indexed from, address indexed to, uint256 value)",
+			`[{"type": "event", "name": "Transfer", "inputs": [
+				{"name": "from", "type": "address", "indexed": true},
+				{"name": "to", "type": "address", "indexed": true},
+				{"name": "value", "type": "uint256"}
+			]}]`,
+		},
+		{
+			"function swap(tuple(address tokenIn, address tokenOut, uint24 fee) key, tuple(uint256 amount, bytes data)[] steps) returns (uint256 amountOut)",
+			`[{"type": "function", "name": "swap", "inputs": [
+				{"name": "key", "type": "tuple", "components": [
+					{"name": "tokenIn", "type": "address"},
+					{"name": "tokenOut", "type": "address"},
+					{"name": "fee", "type": "uint24"}
+				]},
+				{"name": "steps", "type": "tuple[]", "components": [
+					{"name": "amount", "type": "uint256"},
+					{"name": "data", "type": "bytes"}
+				]}
+			], "outputs": [
+				{"name": "amountOut", "type": "uint256"}
+			]}]`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fullsig, func(t *testing.T) {
+			var _abi, err = abi.JSON(strings.NewReader(test.field))
+			assert.NoError(t, err)
+
+			if len(_abi.Events) > 0 {
+				var evt = lo.Values(_abi.Events)[0]
+				res, err := ParseEvent(test.fullsig)
+				assert.NoError(t, err)
+				assert.Equal(t, evt, res)
+				assert.Equal(t, test.fullsig, StringifyEvent(&res, WithNames(true)))
+			} else {
+				var meth = lo.Values(_abi.Methods)[0]
+				res, err := ParseMethod(test.fullsig)
+				assert.NoError(t, err)
+				assert.Equal(t, meth, res)
+				assert.Equal(t, test.fullsig, StringifyMethod(&res, WithNames(true)))
+			}
+		})
+	}
+
+	// names are optional and can be mixed with unnamed parameters
+	res, err := ParseMethod("function f((uint256,bool flag) a, address)")
+	assert.NoError(t, err)
+	assert.Equal(t, "a", res.Inputs[0].Name)
+	assert.Equal(t, []string{"arg0", "flag"}, res.Inputs[0].Type.TupleRawNames)
+	assert.Equal(t, "arg1", res.Inputs[1].Name)
+	assert.Equal(t, "function f((uint256,bool),address)", StringifyMethod(&res))
+
+	// data locations are skipped rather than taken as names
+	res, err = ParseMethod("function f(string memory, bytes calldata data, (uint256,bool) memory p) external view returns (uint256[] memory)")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"arg0", "data", "p"}, lo.Map(res.Inputs, func(arg abi.Argument, _ int) string { return arg.Name }))
+	assert.Equal(t, "arg0", res.Outputs[0].Name)
+	assert.Equal(t, "f(string,bytes,(uint256,bool))", res.Sig)
+}
+
+func TestParseFragment(t *testing.T) {
+	var tests = []struct {
+		fullsig string
+		field   string
+		get     func(abi.ABI) Fragment
+	}{
+		{
+			"error InsufficientBalance(uint256,uint256)",
+			`[{"type": "error", "name": "InsufficientBalance", "inputs": [{"name": "arg0", "type": "uint256"}, {"name": "arg1", "type": "uint256"}]}]`,
+			func(a abi.ABI) Fragment { var e = a.Errors["InsufficientBalance"]; return Fragment{Error: &e} },
+		},
+		{
+			"constructor(address,(uint256,bool))",
+			`[{"type": "constructor", "stateMutability": "nonpayable", "inputs": [{"name": "arg0", "type": "address"}, {"name": "arg1", "type": "tuple", "components": [{"name": "arg0", "type": "uint256"}, {"name": "arg1", "type": "bool"}]}]}]`,
+			func(a abi.ABI) Fragment { return Fragment{Method: &a.Constructor} },
+		},
+		{
+			"constructor() payable",
+			`[{"type": "constructor", "stateMutability": "payable", "payable": true}]`,
+			func(a abi.ABI) Fragment { return Fragment{Method: &a.Constructor} },
+		},
+		{
+			"fallback() external",
+			`[{"type": "fallback", "stateMutability": "nonpayable"}]`,
+			func(a abi.ABI) Fragment { return Fragment{Method: &a.Fallback} },
+		},
+		{
+			"fallback() external payable",
+			`[{"type": "fallback", "stateMutability": "payable", "payable": true}]`,
+			func(a abi.ABI) Fragment { return Fragment{Method: &a.Fallback} },
+		},
+		{
+			"receive() external payable",
+			`[{"type": "receive", "stateMutability": "payable", "payable": true}]`,
+			func(a abi.ABI) Fragment { return Fragment{Method: &a.Receive} },
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fullsig, func(t *testing.T) {
+			var _abi, err = abi.JSON(strings.NewReader(test.field))
+			assert.NoError(t, err)
+
+			res, err := ParseFragment(test.fullsig)
+			assert.NoError(t, err)
+			assert.Equal(t, test.get(_abi), res)
+			assert.Equal(t, test.fullsig, StringifyFragment(res))
+		})
+	}
+
+	_, err := ParseFragment("struct S(uint256)")
+	assert.Error(t, err)
+
+	_, err = ParseFallback("fallback(uint256)")
+	assert.Error(t, err)
+}
+
+func TestParseStateMutability(t *testing.T) {
+	var tests = []struct {
+		fullsig         string
+		stateMutability string
+		stringified     string
+	}{
+		{"function balanceOf(address) view returns (uint256)", "view", "function balanceOf(address) view returns (uint256)"},
+		{"function balanceOf(address) external view returns (uint256)", "view", "function balanceOf(address) view returns (uint256)"},
+		{"function add(uint256,uint256) public pure returns (uint256)", "pure", "function add(uint256,uint256) pure returns (uint256)"},
+		{"function deposit() payable", "payable", "function deposit() payable"},
+		{"function transfer(address,uint256) nonpayable returns (bool)", "nonpayable", "function transfer(address,uint256)(bool)"},
+		{"function transfer(address,uint256) returns (bool)", "", "function transfer(address,uint256)(bool)"},
+		{"function symbol()(string)", "", "function symbol()(string)"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fullsig, func(t *testing.T) {
+			res, err := ParseMethod(test.fullsig)
+			assert.NoError(t, err)
+			assert.Equal(t, test.stateMutability, res.StateMutability)
+			assert.Equal(t, test.stringified, StringifyMethod(&res))
+
+			again, err := ParseMethod(StringifyMethod(&res))
+			assert.NoError(t, err)
+			assert.Equal(t, res.Sig, again.Sig)
+			assert.Equal(t, res.IsConstant(), again.IsConstant())
+			assert.Equal(t, res.IsPayable(), again.IsPayable())
+		})
+	}
+
+	var meth, err = ParseMethod("function balanceOf(address) view returns (uint256)")
+	assert.NoError(t, err)
+	assert.True(t, meth.IsConstant())
+	assert.Len(t, meth.Outputs, 1)
+
+	for _, s := range []string{
+		"function f() view pure",
+		"function f() returns uint256",
+		"function f() view returns (uint256) external",
+	} {
+		_, err := ParseMethod(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	var tests = []struct {
+		sig   string
+		err   string
+		caret string
+	}{
+		{
+			"function transfer(address to uint256 amount)",
+			"column 30: wanted token ',' or ')' but got uint256",
+			"function transfer(address to uint256 amount)\n                             ^^^^^^^",
+		},
+		{
+			"event Transfer(address,address",
+			"column 31: wanted token ',' or ')' but got EOF",
+			"event Transfer(address,address\n                              ^",
+		},
+		{
+			"function f() view pure",
+			"column 19: state mutability specified twice: view and pure",
+			"function f() view pure\n                  ^^^^",
+		},
+		{
+			"event E(address,(uint256 _))",
+			"column 17: invalid type tuple",
+			"event E(address,(uint256 _))\n                ^",
+		},
+		{
+			"error E(fixed8x81)",
+			"column 9: invalid type fixed8x81",
+			"error E(fixed8x81)\n        ^^^^^^^^^",
+		},
+	}
+
+	for _, test := range tests {
+		_, err := ParseFragment(test.sig)
+
+		var pe *ParseError
+
+		if assert.ErrorAs(t, err, &pe, test.sig) {
+			assert.ErrorContains(t, err, test.err)
+			assert.Equal(t, test.caret, pe.Caret())
+		}
+	}
+
+	_, err := ParseSolidity("interface I {\n\tfunction f(uint256 a b) external;\n}")
+
+	var pe *ParseError
+
+	if assert.ErrorAs(t, err, &pe) {
+		assert.Equal(t, 2, pe.Line)
+		assert.Equal(t, "\tfunction f(uint256 a b) external;\n\t                     ^", pe.Caret())
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	var tests = []struct {
+		sig       string
+		canonical string
+		topic     string
+	}{
+		{
+			"function transfer(address to, uint amount) external returns (bool)",
+			"transfer(address,uint256)",
+			"0xa9059cbb2ab09eb219583f4a59a5d0623ade346d962bcd4e46b11da047c9049b",
+		},
+		{
+			" transfer( address , uint256 ) ",
+			"transfer(address,uint256)",
+			"0xa9059cbb2ab09eb219583f4a59a5d0623ade346d962bcd4e46b11da047c9049b",
+		},
+		{
+			"event Transfer(address indexed from, address indexed to, uint value)",
+			"Transfer(address,address,uint256)",
+			"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+		},
+		{
+			"Transfer(address indexed, address indexed, uint256)",
+			"Transfer(address,address,uint256)",
+			"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+		},
+		{
+			"function transfer(address to, string memory memo) external",
+			"transfer(address,string)",
+			"",
+		},
+		{
+			"error InsufficientBalance(uint256 available, uint256 required)",
+			"InsufficientBalance(uint256,uint256)",
+			"",
+		},
+		{
+			"swap(tuple(address currency0, address currency1, uint24 fee, int24 tickSpacing, address hooks) key, (bool,int,uint160) params, bytes hookData)",
+			"swap((address,address,uint24,int24,address),(bool,int256,uint160),bytes)",
+			"",
+		},
+		{
+			"f(byte, int[2], fixed)",
+			"f(bytes1,int256[2],fixed128x18)",
+			"",
+		},
+	}
+
+	for _, test := range tests {
+		canonical, err := Canonicalize(test.sig)
+		assert.NoError(t, err, test.sig)
+		assert.Equal(t, test.canonical, canonical)
+
+		if len(test.topic) == 0 {
+			continue
+		}
+
+		topic, err := Topic(test.sig)
+		assert.NoError(t, err)
+		assert.Equal(t, test.topic, topic.Hex())
+
+		selector, err := Selector(test.sig)
+		assert.NoError(t, err)
+		assert.Equal(t, test.topic[:10], "0x"+hex.EncodeToString(selector[:]))
+	}
+
+	_, err := Canonicalize("constructor(uint256 supply)")
+	assert.ErrorContains(t, err, "constructor has no canonical signature")
+
+	_, err = Selector("transfer(address to uint256)")
+
+	var pe *ParseError
+
+	if assert.ErrorAs(t, err, &pe) {
+		assert.Equal(t, 21, pe.Column)
+		assert.Equal(t, "transfer(address to uint256)\n                    ^^^^^^^", pe.Caret())
+	}
+}
@@ -0,0 +1,166 @@
+package fullsig
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/samber/lo"
+)
+
+// Signatures holds the fragments imported from a signature dump
+type Signatures struct {
+	Methods []eth_abi.Method
+	Events  []eth_abi.Event
+	Errors  []eth_abi.Error
+
+	// Malformed holds one error per line that could not be imported
+	Malformed []*LineError
+}
+
+type LineError struct {
+	Line int
+	Text string
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %s: %s", e.Line, e.Err, e.Text)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// ImportSignatures reads a text dump of canonical signatures, as shipped by public signature databases:
+//
+//	transfer(address,uint256)
+//	0xa9059cbb transfer(address,uint256)
+//	event Transfer(address,address,uint256)
+//	0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef Transfer(address,address,uint256)
+//	error InsufficientBalance(uint256)
+//
+// Each line holds a signature, optionally tagged with the function, event or error keyword, and optionally preceded
+// by its hex selector or topic0, which is then verified. Untagged signatures are events when preceded by a 32-byte
+// topic0, and functions otherwise.
+// Empty lines and lines starting with '#' are skipped. Parameters get positional names (arg0, arg1...).
+// Malformed lines are reported in the result; the returned error is only set if reading fails.
+func ImportSignatures(r io.Reader) (*Signatures, error) {
+	var (
+		res     Signatures
+		scanner = bufio.NewScanner(r)
+		line    = 0
+	)
+
+	scanner.Buffer(nil, 1024*1024)
+
+	for scanner.Scan() {
+		line++
+
+		var text = strings.TrimSpace(scanner.Text())
+
+		if len(text) == 0 || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if err := importSignature(&res, text); err != nil {
+			res.Malformed = append(res.Malformed, &LineError{Line: line, Text: text, Err: err})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func importSignature(res *Signatures, text string) error {
+	var (
+		id  []byte
+		sig = text
+	)
+
+	if strings.HasPrefix(sig, "0x") {
+		var i = strings.IndexAny(sig, " \t,:")
+
+		if i == -1 {
+			return fmt.Errorf("missing signature after id")
+		}
+
+		b, err := hexutil.Decode(sig[:i])
+
+		if err != nil {
+			return fmt.Errorf("invalid id: %w", err)
+		}
+
+		id = b
+		sig = strings.TrimSpace(strings.TrimLeft(sig[i:], " \t,:"))
+	}
+
+	var keyword string
+
+	switch fields := strings.Fields(sig); {
+	case len(fields) > 0 && lo.Contains([]string{"function", "event", "error"}, fields[0]):
+		keyword = fields[0]
+	case len(id) == 32:
+		keyword, sig = "event", "event "+sig
+	default:
+		keyword, sig = "function", "function "+sig
+	}
+
+	switch keyword {
+	case "event":
+		evt, err := ParseEvent(sig)
+
+		if err != nil {
+			return err
+		}
+
+		if err := checkImportedID(id, evt.ID[:]); err != nil {
+			return err
+		}
+
+		res.Events = append(res.Events, evt)
+
+	case "error":
+		e, err := ParseErrorFragment(sig)
+
+		if err != nil {
+			return err
+		}
+
+		if err := checkImportedID(id, e.ID[:4]); err != nil {
+			return err
+		}
+
+		res.Errors = append(res.Errors, e)
+
+	default:
+		meth, err := ParseMethod(sig)
+
+		if err != nil {
+			return err
+		}
+
+		if err := checkImportedID(id, meth.ID); err != nil {
+			return err
+		}
+
+		res.Methods = append(res.Methods, meth)
+	}
+
+	return nil
+}
+
+func checkImportedID(id []byte, expected []byte) error {
+	if id != nil && !bytes.Equal(id, expected) {
+		return fmt.Errorf("id mismatch: signature has id %s", hexutil.Encode(expected))
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package fullsig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportSignatures(t *testing.T) {
+	var dump = strings.Join([]string{
+		"# 4byte dump",
+		"transfer(address,uint256)",
+		"0xa9059cbb transfer(address,uint256)",
+		"0x095ea7b3,approve(address,uint256)",
+		"",
+		"function balanceOf(address)",
+		"event Transfer(address,address,uint256)",
+		"error InsufficientBalance(uint256)",
+		"transfer(address,uint256",
+		"0x12345678 transfer(address,uint256)",
+		"foo(bar)",
+		"event Transfer(address)(uint256)",
+		"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef Transfer(address,address,uint256)",
+		"event\tApproval(address,address,uint256)",
+		"0x0000000000000000000000000000000000000000000000000000000000000001 Transfer(address,address,uint256)",
+	}, "\n")
+
+	res, err := ImportSignatures(strings.NewReader(dump))
+	assert.NoError(t, err)
+
+	assert.Len(t, res.Methods, 4)
+	assert.Equal(t, "transfer(address,uint256)", res.Methods[0].Sig)
+	assert.Equal(t, "arg1", res.Methods[0].Inputs[1].Name)
+	assert.Equal(t, "approve(address,uint256)", res.Methods[2].Sig)
+	assert.Equal(t, "balanceOf(address)", res.Methods[3].Sig)
+
+	assert.Len(t, res.Events, 3)
+	assert.Equal(t, "Transfer(address,address,uint256)", res.Events[0].Sig)
+	assert.Equal(t, "Transfer(address,address,uint256)", res.Events[1].Sig)
+	assert.Equal(t, "Approval(address,address,uint256)", res.Events[2].Sig)
+
+	assert.Len(t, res.Errors, 1)
+	assert.Equal(t, "InsufficientBalance(uint256)", res.Errors[0].Sig)
+
+	assert.Len(t, res.Malformed, 5)
+	assert.Equal(t, []int{9, 10, 11, 12, 15}, lo.Map(res.Malformed, func(e *LineError, _ int) int { return e.Line }))
+	assert.ErrorContains(t, res.Malformed[1], "line 10: id mismatch")
+	assert.ErrorContains(t, res.Malformed[4], "line 15: id mismatch: signature has id 0xddf252ad")
+}
@@ -1,10 +1,10 @@
 package fullsig
 
 import (
-	"encoding/json"
-	"fmt"
+	"regexp"
 	"strconv"
 
+	"github.com/agnosticeng/evmabi/abi"
 	"github.com/bzick/tokenizer"
 	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/samber/lo"
@@ -27,6 +27,8 @@ var (
 			"bytes",
 			"uint",
 			"function",
+			"fixed",
+			"ufixed",
 		},
 		lo.RepeatBy(32, func(i int) string {
 			return "uint" + strconv.FormatUint(uint64((i+1)*8), 10)
@@ -39,6 +41,15 @@ var (
 		}),
 	})
 
+	FIXED_TYPENAME_REGEX = regexp.MustCompile(`^u?fixed[0-9]+x[0-9]+$`)
+
+	// TYPE_ALIASES maps Solidity shorthand type names to their canonical name
+	TYPE_ALIASES = map[string]string{
+		"uint": "uint256",
+		"int":  "int256",
+		"byte": "bytes1",
+	}
+
 	tknz = tokenizer.New().
 		DefineTokens(TokenOpenParens, []string{"("}).
 		DefineTokens(TokenCloseParens, []string{")"}).
@@ -51,20 +62,46 @@ var (
 func isEventToken(t *tokenizer.Token) bool    { return t.IsKeyword() && t.ValueString() == "event" }
 func isFunctionToken(t *tokenizer.Token) bool { return t.IsKeyword() && t.ValueString() == "function" }
 func isIndexedToken(t *tokenizer.Token) bool  { return t.IsKeyword() && t.ValueString() == "indexed" }
+func isTupleToken(t *tokenizer.Token) bool    { return t.IsKeyword() && t.ValueString() == "tuple" }
+func isErrorToken(t *tokenizer.Token) bool    { return t.IsKeyword() && t.ValueString() == "error" }
+func isPayableToken(t *tokenizer.Token) bool  { return t.IsKeyword() && t.ValueString() == "payable" }
+func isExternalToken(t *tokenizer.Token) bool { return t.IsKeyword() && t.ValueString() == "external" }
+func isReturnsToken(t *tokenizer.Token) bool  { return t.IsKeyword() && t.ValueString() == "returns" }
+func isVisibilityToken(t *tokenizer.Token) bool {
+	return t.IsKeyword() && lo.Contains([]string{"external", "public"}, t.ValueString())
+}
+func isStateMutabilityToken(t *tokenizer.Token) bool {
+	return t.IsKeyword() && lo.Contains([]string{"pure", "view", "payable", "nonpayable"}, t.ValueString())
+}
+func isDataLocationToken(t *tokenizer.Token) bool {
+	return t.IsKeyword() && lo.Contains([]string{"memory", "calldata", "storage"}, t.ValueString())
+}
+func isConstructorToken(t *tokenizer.Token) bool {
+	return t.IsKeyword() && t.ValueString() == "constructor"
+}
+func isFallbackToken(t *tokenizer.Token) bool { return t.IsKeyword() && t.ValueString() == "fallback" }
+func isReceiveToken(t *tokenizer.Token) bool  { return t.IsKeyword() && t.ValueString() == "receive" }
+func isAnonymousToken(t *tokenizer.Token) bool {
+	return t.IsKeyword() && t.ValueString() == "anonymous"
+}
 func isScalarTypeName(t *tokenizer.Token) bool {
-	return lo.Contains(SCALAR_TYPENAMES, t.ValueString())
+	return lo.Contains(SCALAR_TYPENAMES, t.ValueString()) ||
+		FIXED_TYPENAME_REGEX.MatchString(t.ValueString()) ||
+		lo.HasKey(TYPE_ALIASES, t.ValueString())
 }
 
-func ParseEvent(s string) (eth_abi.Event, error) {
+func ParseEvent(s string) (_ eth_abi.Event, err error) {
+	defer func() { err = positioned(err, s) }()
+
 	var stream = tknz.ParseString(s)
 	defer stream.Close()
 
 	if !isEventToken(stream.CurrentToken()) {
-		return eth_abi.Event{}, fmt.Errorf("event fullsig must start with keyword 'event': %s", s)
+		return eth_abi.Event{}, parseErrorf(stream.CurrentToken(), "event fullsig must start with keyword 'event'")
 	}
 
 	if !stream.GoNext().CurrentToken().IsKeyword() {
-		return eth_abi.Event{}, fmt.Errorf("wanted event name but got: %s", string(stream.CurrentToken().Value()))
+		return eth_abi.Event{}, parseErrorf(stream.CurrentToken(), "wanted event name but got %s", tokenString(stream.CurrentToken()))
 	}
 
 	var eventName = stream.CurrentToken().ValueString()
@@ -75,23 +112,31 @@ func ParseEvent(s string) (eth_abi.Event, error) {
 		return eth_abi.Event{}, err
 	}
 
-	if !stream.GoNext().IsValid() {
-		return eth_abi.Event{}, fmt.Errorf("wanted EOF but got %s", string(stream.CurrentToken().Value()))
+	var anonymous = isAnonymousToken(stream.CurrentToken())
+
+	if anonymous {
+		stream.GoNext()
+	}
+
+	if stream.IsValid() {
+		return eth_abi.Event{}, parseErrorf(stream.CurrentToken(), "wanted EOF but got %s", tokenString(stream.CurrentToken()))
 	}
 
-	return eth_abi.NewEvent(eventName, eventName, false, inputs), nil
+	return eth_abi.NewEvent(eventName, eventName, anonymous, inputs), nil
 }
 
-func ParseMethod(s string) (eth_abi.Method, error) {
+func ParseMethod(s string) (_ eth_abi.Method, err error) {
+	defer func() { err = positioned(err, s) }()
+
 	var stream = tknz.ParseString(s)
 	defer stream.Close()
 
 	if !isFunctionToken(stream.CurrentToken()) {
-		return eth_abi.Method{}, fmt.Errorf("function fullsig must start with keyword 'function': %s", s)
+		return eth_abi.Method{}, parseErrorf(stream.CurrentToken(), "function fullsig must start with keyword 'function'")
 	}
 
 	if !stream.GoNext().CurrentToken().IsKeyword() {
-		return eth_abi.Method{}, fmt.Errorf("wanted function name but got: %s", string(stream.CurrentToken().Value()))
+		return eth_abi.Method{}, parseErrorf(stream.CurrentToken(), "wanted function name but got %s", tokenString(stream.CurrentToken()))
 	}
 
 	var functionName = stream.CurrentToken().ValueString()
@@ -102,8 +147,23 @@ func ParseMethod(s string) (eth_abi.Method, error) {
 		return eth_abi.Method{}, err
 	}
 
+	stateMutability, err := parseModifiers(stream)
+
+	if err != nil {
+		return eth_abi.Method{}, err
+	}
+
 	var outputs eth_abi.Arguments
 
+	// outputs are either introduced by the returns keyword or given as a bare second paren group
+	if isReturnsToken(stream.CurrentToken()) {
+		stream.GoNext()
+
+		if !stream.CurrentToken().Is(TokenOpenParens) {
+			return eth_abi.Method{}, parseErrorf(stream.CurrentToken(), "wanted token '(' after 'returns' but got %s", tokenString(stream.CurrentToken()))
+		}
+	}
+
 	if stream.CurrentToken().Is(TokenOpenParens) {
 		outputs, err = newArguments(stream)
 
@@ -112,11 +172,37 @@ func ParseMethod(s string) (eth_abi.Method, error) {
 		}
 	}
 
-	if !stream.GoNext().IsValid() {
-		return eth_abi.Method{}, fmt.Errorf("wanted EOF but got %s", string(stream.CurrentToken().Value()))
+	if stream.IsValid() {
+		return eth_abi.Method{}, parseErrorf(stream.CurrentToken(), "wanted EOF but got %s", tokenString(stream.CurrentToken()))
 	}
 
-	return eth_abi.NewMethod(functionName, functionName, eth_abi.Function, "", false, false, inputs, outputs), nil
+	return eth_abi.NewMethod(functionName, functionName, eth_abi.Function, stateMutability, false, false, inputs, outputs), nil
+}
+
+// parseModifiers consumes visibility (external, public) and state mutability (pure, view, payable, nonpayable)
+// modifiers, in any order, and returns the state mutability
+func parseModifiers(stream *tokenizer.Stream) (string, error) {
+	var stateMutability string
+
+	for {
+		var tok = stream.CurrentToken()
+
+		switch {
+		case isVisibilityToken(tok):
+			stream.GoNext()
+
+		case isStateMutabilityToken(tok):
+			if len(stateMutability) > 0 {
+				return "", parseErrorf(tok, "state mutability specified twice: %s and %s", stateMutability, tok.ValueString())
+			}
+
+			stateMutability = tok.ValueString()
+			stream.GoNext()
+
+		default:
+			return stateMutability, nil
+		}
+	}
 }
 
 func newArguments(stream *tokenizer.Stream) (eth_abi.Arguments, error) {
@@ -133,11 +219,9 @@ func newArguments(stream *tokenizer.Stream) (eth_abi.Arguments, error) {
 	var res = make([]eth_abi.Argument, len(args))
 
 	for i, arg := range args {
-		t, err := eth_abi.NewType(arg.Type, arg.InternalType, arg.Components)
+		t, err := abi.NewType(arg.Type, arg.InternalType, arg.Components)
 
 		if err != nil {
-			js, _ := json.MarshalIndent(arg.Components, "", "    ")
-			fmt.Println(string(js))
 			return nil, err
 		}
 
@@ -154,19 +238,23 @@ func newArguments(stream *tokenizer.Stream) (eth_abi.Arguments, error) {
 
 func parseArgument(stream *tokenizer.Stream) (eth_abi.ArgumentMarshaling, error) {
 	var (
-		res eth_abi.ArgumentMarshaling
-		err error
+		res   eth_abi.ArgumentMarshaling
+		start = stream.CurrentToken()
+		err   error
 	)
 
 	switch {
 	case isScalarTypeName(stream.CurrentToken()):
-		res.Type = stream.CurrentToken().ValueString()
+		res.Type = lo.ValueOr(TYPE_ALIASES, stream.CurrentToken().ValueString(), stream.CurrentToken().ValueString())
 		stream.GoNext()
 	case stream.CurrentToken().Is(TokenOpenParens):
 		res.Type = "tuple"
 		res.Components, err = parseArguments(stream)
+	case isTupleToken(stream.CurrentToken()) && stream.NextToken().Is(TokenOpenParens):
+		res.Type = "tuple"
+		res.Components, err = parseArguments(stream.GoNext())
 	default:
-		return res, fmt.Errorf("invalid token: %s", string(stream.CurrentToken().Value()))
+		return res, parseErrorf(stream.CurrentToken(), "wanted type name but got %s", tokenString(stream.CurrentToken()))
 	}
 
 	if err != nil {
@@ -183,8 +271,20 @@ func parseArgument(stream *tokenizer.Stream) (eth_abi.ArgumentMarshaling, error)
 		res.Type = res.Type + s
 	}
 
-	if isIndexedToken(stream.CurrentToken()) {
-		res.Indexed = true
+	// the type is checked here rather than in newArguments so that the error points at the argument
+	if _, err := abi.NewType(res.Type, res.InternalType, res.Components); err != nil {
+		return res, parseErrorf(start, "invalid type %s: %s", res.Type, err)
+	}
+
+	// data locations, as in Solidity signatures, are not part of the ABI
+	for isIndexedToken(stream.CurrentToken()) || isDataLocationToken(stream.CurrentToken()) {
+		res.Indexed = res.Indexed || isIndexedToken(stream.CurrentToken())
+		stream.GoNext()
+	}
+
+	// optional parameter name, as in the ethers.js human-readable ABI format
+	if stream.CurrentToken().IsKeyword() {
+		res.Name = stream.CurrentToken().ValueString()
 		stream.GoNext()
 	}
 
@@ -193,7 +293,7 @@ func parseArgument(stream *tokenizer.Stream) (eth_abi.ArgumentMarshaling, error)
 
 func parseArraySuffix(stream *tokenizer.Stream) (string, error) {
 	if !stream.CurrentToken().Is(TokenOpenBracket) {
-		return "", fmt.Errorf("wanted token '[' but got %s", string(stream.CurrentToken().Value()))
+		return "", parseErrorf(stream.CurrentToken(), "wanted token '[' but got %s", tokenString(stream.CurrentToken()))
 	}
 
 	stream.GoNext()
@@ -208,7 +308,7 @@ func parseArraySuffix(stream *tokenizer.Stream) (string, error) {
 		return "[" + strconv.FormatInt(i, 10) + "]", nil
 
 	default:
-		return "", fmt.Errorf("wanted token ']' or integer then ']' but got %s", string(stream.CurrentToken().Value()))
+		return "", parseErrorf(stream.CurrentToken(), "wanted token ']' or integer then ']' but got %s", tokenString(stream.CurrentToken()))
 	}
 }
 
@@ -216,7 +316,7 @@ func parseArguments(stream *tokenizer.Stream) ([]eth_abi.ArgumentMarshaling, err
 	var res []eth_abi.ArgumentMarshaling
 
 	if !stream.CurrentToken().Is(TokenOpenParens) {
-		return nil, fmt.Errorf("wanted token '(' but got %s", string(stream.CurrentToken().Value()))
+		return nil, parseErrorf(stream.CurrentToken(), "wanted token '(' but got %s", tokenString(stream.CurrentToken()))
 	}
 
 	stream.GoNext()
@@ -246,7 +346,7 @@ func parseArguments(stream *tokenizer.Stream) ([]eth_abi.ArgumentMarshaling, err
 			stream.GoNext()
 			return res, nil
 		default:
-			return nil, fmt.Errorf("wanted token ',' or ')' but got %s", string(stream.CurrentToken().Value()))
+			return nil, parseErrorf(stream.CurrentToken(), "wanted token ',' or ')' but got %s", tokenString(stream.CurrentToken()))
 		}
 	}
 }
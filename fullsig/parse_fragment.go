@@ -0,0 +1,201 @@
+package fullsig
+
+import (
+	"github.com/bzick/tokenizer"
+	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// Fragment holds the result of ParseFragment: exactly one of its fields is set
+type Fragment struct {
+	Event  *eth_abi.Event
+	Method *eth_abi.Method
+	Error  *eth_abi.Error
+}
+
+// ParseFragment parses any fragment fullsig, dispatching on its leading keyword:
+// event, function, error, constructor, fallback or receive
+func ParseFragment(s string) (_ Fragment, err error) {
+	defer func() { err = positioned(err, s) }()
+
+	var stream = tknz.ParseString(s)
+	defer stream.Close()
+
+	var tok = stream.CurrentToken()
+
+	switch {
+	case isEventToken(tok):
+		evt, err := ParseEvent(s)
+
+		if err != nil {
+			return Fragment{}, err
+		}
+
+		return Fragment{Event: &evt}, nil
+
+	case isErrorToken(tok):
+		e, err := ParseErrorFragment(s)
+
+		if err != nil {
+			return Fragment{}, err
+		}
+
+		return Fragment{Error: &e}, nil
+
+	case isFunctionToken(tok):
+		return methodFragment(ParseMethod(s))
+
+	case isConstructorToken(tok):
+		return methodFragment(ParseConstructor(s))
+
+	case isFallbackToken(tok):
+		return methodFragment(ParseFallback(s))
+
+	case isReceiveToken(tok):
+		return methodFragment(ParseReceive(s))
+
+	default:
+		return Fragment{}, parseErrorf(stream.CurrentToken(), "fullsig must start with one of the keywords event, function, error, constructor, fallback or receive")
+	}
+}
+
+func methodFragment(meth eth_abi.Method, err error) (Fragment, error) {
+	if err != nil {
+		return Fragment{}, err
+	}
+
+	return Fragment{Method: &meth}, nil
+}
+
+func ParseErrorFragment(s string) (_ eth_abi.Error, err error) {
+	defer func() { err = positioned(err, s) }()
+
+	var stream = tknz.ParseString(s)
+	defer stream.Close()
+
+	if !isErrorToken(stream.CurrentToken()) {
+		return eth_abi.Error{}, parseErrorf(stream.CurrentToken(), "error fullsig must start with keyword 'error'")
+	}
+
+	if !stream.GoNext().CurrentToken().IsKeyword() {
+		return eth_abi.Error{}, parseErrorf(stream.CurrentToken(), "wanted error name but got %s", tokenString(stream.CurrentToken()))
+	}
+
+	var errorName = stream.CurrentToken().ValueString()
+	stream.GoNext()
+	inputs, err := newArguments(stream)
+
+	if err != nil {
+		return eth_abi.Error{}, err
+	}
+
+	if stream.IsValid() {
+		return eth_abi.Error{}, parseErrorf(stream.CurrentToken(), "wanted EOF but got %s", tokenString(stream.CurrentToken()))
+	}
+
+	return eth_abi.NewError(errorName, inputs), nil
+}
+
+func ParseConstructor(s string) (_ eth_abi.Method, err error) {
+	defer func() { err = positioned(err, s) }()
+
+	var stream = tknz.ParseString(s)
+	defer stream.Close()
+
+	if !isConstructorToken(stream.CurrentToken()) {
+		return eth_abi.Method{}, parseErrorf(stream.CurrentToken(), "constructor fullsig must start with keyword 'constructor'")
+	}
+
+	stream.GoNext()
+	inputs, err := newArguments(stream)
+
+	if err != nil {
+		return eth_abi.Method{}, err
+	}
+
+	var payable = parsePayable(stream)
+
+	if stream.IsValid() {
+		return eth_abi.Method{}, parseErrorf(stream.CurrentToken(), "wanted EOF but got %s", tokenString(stream.CurrentToken()))
+	}
+
+	return eth_abi.NewMethod("", "", eth_abi.Constructor, mutability(payable), false, payable, inputs, nil), nil
+}
+
+// ParseFallback parses a fallback declaration, e.g. "fallback() external payable"
+func ParseFallback(s string) (_ eth_abi.Method, err error) {
+	defer func() { err = positioned(err, s) }()
+
+	var stream = tknz.ParseString(s)
+	defer stream.Close()
+
+	if !isFallbackToken(stream.CurrentToken()) {
+		return eth_abi.Method{}, parseErrorf(stream.CurrentToken(), "fallback fullsig must start with keyword 'fallback'")
+	}
+
+	if err := parseEmptyArguments(stream.GoNext()); err != nil {
+		return eth_abi.Method{}, err
+	}
+
+	var payable = parsePayable(stream)
+
+	if stream.IsValid() {
+		return eth_abi.Method{}, parseErrorf(stream.CurrentToken(), "wanted EOF but got %s", tokenString(stream.CurrentToken()))
+	}
+
+	return eth_abi.NewMethod("", "", eth_abi.Fallback, mutability(payable), false, payable, nil, nil), nil
+}
+
+// ParseReceive parses a receive declaration, e.g. "receive() external payable"; receive functions are always payable
+func ParseReceive(s string) (_ eth_abi.Method, err error) {
+	defer func() { err = positioned(err, s) }()
+
+	var stream = tknz.ParseString(s)
+	defer stream.Close()
+
+	if !isReceiveToken(stream.CurrentToken()) {
+		return eth_abi.Method{}, parseErrorf(stream.CurrentToken(), "receive fullsig must start with keyword 'receive'")
+	}
+
+	if err := parseEmptyArguments(stream.GoNext()); err != nil {
+		return eth_abi.Method{}, err
+	}
+
+	parsePayable(stream)
+
+	if stream.IsValid() {
+		return eth_abi.Method{}, parseErrorf(stream.CurrentToken(), "wanted EOF but got %s", tokenString(stream.CurrentToken()))
+	}
+
+	return eth_abi.NewMethod("", "", eth_abi.Receive, "payable", false, true, nil, nil), nil
+}
+
+func parseEmptyArguments(stream *tokenizer.Stream) error {
+	if !stream.CurrentToken().Is(TokenOpenParens) || !stream.NextToken().Is(TokenCloseParens) {
+		return parseErrorf(stream.CurrentToken(), "wanted token '(' then ')' but got %s", tokenString(stream.CurrentToken()))
+	}
+
+	stream.GoNext().GoNext()
+	return nil
+}
+
+// parsePayable consumes the optional "external" and "payable" modifiers
+func parsePayable(stream *tokenizer.Stream) bool {
+	if isExternalToken(stream.CurrentToken()) {
+		stream.GoNext()
+	}
+
+	if !isPayableToken(stream.CurrentToken()) {
+		return false
+	}
+
+	stream.GoNext()
+	return true
+}
+
+func mutability(payable bool) string {
+	if payable {
+		return "payable"
+	}
+
+	return "nonpayable"
+}
@@ -0,0 +1,1323 @@
+package fullsig
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/agnosticeng/evmabi/abi"
+	"github.com/bzick/tokenizer"
+	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/samber/lo"
+)
+
+const (
+	TokenOpenBrace  = 6
+	TokenCloseBrace = 7
+	TokenSemicolon  = 8
+	TokenDot        = 9
+	TokenArrow      = 10
+	TokenComment    = 11
+	TokenQuote      = 12
+)
+
+var (
+	solidityTknz       = newSolidityTokenizer()
+	arraySuffixesRegex = regexp.MustCompile(`^(\[[0-9]*\])*$`)
+)
+
+func newSolidityTokenizer() *tokenizer.Tokenizer {
+	var t = tokenizer.New().
+		DefineTokens(TokenOpenParens, []string{"("}).
+		DefineTokens(TokenCloseParens, []string{")"}).
+		DefineTokens(TokenOpenBracket, []string{"["}).
+		DefineTokens(TokenCloseBracket, []string{"]"}).
+		DefineTokens(TokenComma, []string{","}).
+		DefineTokens(TokenOpenBrace, []string{"{"}).
+		DefineTokens(TokenCloseBrace, []string{"}"}).
+		DefineTokens(TokenSemicolon, []string{";"}).
+		DefineTokens(TokenDot, []string{"."}).
+		DefineTokens(TokenArrow, []string{"=>"}).
+		AllowKeywordSymbols([]rune{'_', '$'}, []rune{'$', '_', '$', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9'})
+
+	t.DefineStringToken(TokenComment, "//", "\n")
+	t.DefineStringToken(TokenComment, "/*", "*/")
+	t.DefineStringToken(TokenQuote, `"`, `"`).SetEscapeSymbol(tokenizer.BackSlash)
+	t.DefineStringToken(TokenQuote, "'", "'").SetEscapeSymbol(tokenizer.BackSlash)
+	return t
+}
+
+func isSolidityKeyword(t *tokenizer.Token, keywords ...string) bool {
+	return t.IsKeyword() && lo.Contains(keywords, t.ValueString())
+}
+func isCommentToken(t *tokenizer.Token) bool { return t.IsString() && t.StringKey() == TokenComment }
+
+// SolidityContract is a contract, interface or library declared in Solidity source
+type SolidityContract struct {
+	// Kind is one of contract, interface or library
+	Kind     string
+	Abstract bool
+	Name     string
+
+	// Fields is the JSON ABI of the contract, with internalType set as solc does, e.g. "struct Pool.Key"
+	Fields []*abi.FieldMarshaling
+	ABI    eth_abi.ABI
+
+	// Skipped holds one error per item left out of the ABI because its types could not be resolved,
+	// e.g. a type imported from another file
+	Skipped []error
+}
+
+// ParseSolidity parses the contract, interface and library declarations of a Solidity source file and builds their ABIs,
+// without requiring a compiler. Functions, public state variable getters, events and errors are collected, including
+// the ones inherited from contracts declared in the same source; function bodies are skipped.
+// Structs, enums, user-defined value types and contract types must be declared in the source, and array lengths must
+// be integer literals: items using other types are left out of the ABI and reported in SolidityContract.Skipped.
+func ParseSolidity(src string) ([]*SolidityContract, error) {
+	var stream = solidityTknz.ParseString(src)
+	defer stream.Close()
+
+	var p = solidityParser{
+		src:       src,
+		stream:    stream,
+		decls:     make(map[string]*solidityDecl),
+		contracts: make(map[string]*solidityContract),
+	}
+
+	if err := p.parseSourceUnit(); err != nil {
+		return nil, err
+	}
+
+	var res []*SolidityContract
+
+	for _, c := range p.order {
+		fields, skipped := p.resolveContract(c)
+
+		js, err := json.Marshal(fields)
+
+		if err != nil {
+			return nil, err
+		}
+
+		a, err := abi.JSONABI(js)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", c.kind, c.name, err)
+		}
+
+		res = append(res, &SolidityContract{
+			Kind:     c.kind,
+			Abstract: c.abstract,
+			Name:     c.name,
+			Fields:   fields,
+			ABI:      *a,
+			Skipped:  skipped,
+		})
+	}
+
+	return res, nil
+}
+
+type solidityType struct {
+	// name is an elementary type name or a possibly qualified user-defined type name, e.g. Pool.Key
+	name   string
+	arrays string
+
+	// key and value are set for mappings
+	key   *solidityType
+	value *solidityType
+
+	// fn is set for function types
+	fn *solidityFunctionType
+
+	// offset and token locate the first token of the type in the source, to report resolution errors
+	offset int
+	token  string
+}
+
+type solidityFunctionType struct {
+	external   bool
+	mutability string
+	inputs     []*solidityParam
+	outputs    []*solidityParam
+}
+
+type solidityParam struct {
+	typ     *solidityType
+	name    string
+	indexed bool
+}
+
+// solidityDecl is a user-defined type, indexed by its qualified name
+type solidityDecl struct {
+	// kind is one of struct, enum, type or contract
+	kind       string
+	scope      string
+	name       string
+	members    []*solidityParam
+	underlying *solidityType
+}
+
+type solidityContract struct {
+	kind     string
+	abstract bool
+	name     string
+	bases    []string
+	items    []*solidityItem
+}
+
+type solidityItem struct {
+	// kind is one of function, constructor, fallback, receive, event, error or getter
+	kind       string
+	name       string
+	mutability string
+	anonymous  bool
+	inputs     []*solidityParam
+	outputs    []*solidityParam
+
+	// getter is the type of a public state variable
+	getter *solidityType
+}
+
+type solidityParser struct {
+	src       string
+	stream    *tokenizer.Stream
+	decls     map[string]*solidityDecl
+	contracts map[string]*solidityContract
+	order     []*solidityContract
+}
+
+// tok returns the current token, skipping comments
+func (p *solidityParser) tok() *tokenizer.Token {
+	for isCommentToken(p.stream.CurrentToken()) {
+		p.stream.GoNext()
+	}
+
+	return p.stream.CurrentToken()
+}
+
+func (p *solidityParser) next() {
+	p.tok()
+	p.stream.GoNext()
+}
+
+func (p *solidityParser) errorf(format string, args ...any) error {
+	return positioned(parseErrorf(p.tok(), format, args...), p.src)
+}
+
+// typeErrorf builds a ParseError located on the first token of a type
+func (p *solidityParser) typeErrorf(t *solidityType, format string, args ...any) error {
+	return positioned(&ParseError{Offset: t.offset, Token: t.token, Msg: fmt.Sprintf(format, args...)}, p.src)
+}
+
+func (p *solidityParser) got() string {
+	return tokenString(p.tok())
+}
+
+func (p *solidityParser) expect(key tokenizer.TokenKey, s string) error {
+	if !p.tok().Is(key) {
+		return p.errorf("wanted token '%s' but got %s", s, p.got())
+	}
+
+	p.next()
+	return nil
+}
+
+func (p *solidityParser) expectName(what string) (string, error) {
+	if !p.tok().IsKeyword() {
+		return "", p.errorf("wanted %s but got %s", what, p.got())
+	}
+
+	var name = p.tok().ValueString()
+	p.next()
+	return name, nil
+}
+
+func (p *solidityParser) declare(d *solidityDecl) error {
+	if _, ok := p.decls[d.name]; ok {
+		return p.errorf("%s is declared twice", d.name)
+	}
+
+	p.decls[d.name] = d
+	return nil
+}
+
+func (p *solidityParser) parseSourceUnit() error {
+	for p.tok().IsValid() {
+		var (
+			tok = p.tok()
+			err error
+		)
+
+		switch {
+		case isSolidityKeyword(tok, "abstract"):
+			p.next()
+
+			if !isSolidityKeyword(p.tok(), "contract") {
+				return p.errorf("wanted keyword 'contract' after 'abstract' but got %s", p.got())
+			}
+
+			err = p.parseContract(true)
+
+		case isSolidityKeyword(tok, "contract", "interface", "library"):
+			err = p.parseContract(false)
+
+		case isSolidityKeyword(tok, "struct", "enum", "type"):
+			err = p.parseTypeDefinition("")
+
+		// free functions and file-level events and errors are not part of any ABI unless used
+		case isFunctionToken(tok):
+			_, _, err = p.parseFunction()
+
+		case isEventToken(tok):
+			_, err = p.parseEvent()
+
+		case isErrorToken(tok):
+			_, err = p.parseError()
+
+		case isSolidityKeyword(tok, "pragma", "import", "using"):
+			err = p.skipDirective()
+
+		// file-level constants
+		default:
+			err = p.skipStatement()
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *solidityParser) parseContract(abstract bool) error {
+	var c = solidityContract{kind: p.tok().ValueString(), abstract: abstract}
+	p.next()
+
+	name, err := p.expectName(c.kind + " name")
+
+	if err != nil {
+		return err
+	}
+
+	c.name = name
+
+	if isSolidityKeyword(p.tok(), "is") {
+		p.next()
+
+		for {
+			base, err := p.parseQualifiedName()
+
+			if err != nil {
+				return err
+			}
+
+			c.bases = append(c.bases, base)
+
+			// base constructor arguments
+			if p.tok().Is(TokenOpenParens) {
+				if err := p.skipGroup(); err != nil {
+					return err
+				}
+			}
+
+			if !p.tok().Is(TokenComma) {
+				break
+			}
+
+			p.next()
+		}
+	}
+
+	if err := p.expect(TokenOpenBrace, "{"); err != nil {
+		return err
+	}
+
+	if err := p.declare(&solidityDecl{kind: "contract", name: c.name}); err != nil {
+		return err
+	}
+
+	for !p.tok().Is(TokenCloseBrace) {
+		var (
+			tok  = p.tok()
+			item *solidityItem
+			err  error
+		)
+
+		switch {
+		case !tok.IsValid():
+			return p.errorf("unexpected EOF in %s %s", c.kind, c.name)
+
+		case isSolidityKeyword(tok, "struct", "enum", "type"):
+			err = p.parseTypeDefinition(c.name)
+
+		case isEventToken(tok):
+			item, err = p.parseEvent()
+
+		case isErrorToken(tok):
+			item, err = p.parseError()
+
+		case isFunctionToken(tok) && p.isFunctionTypeVariable():
+			item, err = p.parseStateVariable()
+
+		case isFunctionToken(tok) || isConstructorToken(tok) || isFallbackToken(tok) || isReceiveToken(tok):
+			var visibility string
+
+			item, visibility, err = p.parseFunction()
+
+			if visibility == "internal" || visibility == "private" {
+				item = nil
+			}
+
+		case isSolidityKeyword(tok, "using"):
+			err = p.skipDirective()
+
+		case isSolidityKeyword(tok, "modifier"):
+			err = p.skipStatement()
+
+		default:
+			item, err = p.parseStateVariable()
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if item != nil {
+			c.items = append(c.items, item)
+		}
+	}
+
+	p.next()
+	p.contracts[c.name] = &c
+	p.order = append(p.order, &c)
+	return nil
+}
+
+// parseTypeDefinition parses struct, enum and user-defined value type definitions
+func (p *solidityParser) parseTypeDefinition(scope string) error {
+	var kind = p.tok().ValueString()
+	p.next()
+
+	name, err := p.expectName(kind + " name")
+
+	if err != nil {
+		return err
+	}
+
+	var d = solidityDecl{kind: kind, scope: scope, name: name}
+
+	if len(scope) > 0 {
+		d.name = scope + "." + name
+	}
+
+	switch kind {
+	case "struct":
+		if err := p.expect(TokenOpenBrace, "{"); err != nil {
+			return err
+		}
+
+		for !p.tok().Is(TokenCloseBrace) {
+			t, err := p.parseType()
+
+			if err != nil {
+				return err
+			}
+
+			name, err := p.expectName("struct member name")
+
+			if err != nil {
+				return err
+			}
+
+			d.members = append(d.members, &solidityParam{typ: t, name: name})
+
+			if err := p.expect(TokenSemicolon, ";"); err != nil {
+				return err
+			}
+		}
+
+		p.next()
+
+	case "enum":
+		if !p.tok().Is(TokenOpenBrace) {
+			return p.errorf("wanted token '{' but got %s", p.got())
+		}
+
+		if err := p.skipGroup(); err != nil {
+			return err
+		}
+
+	case "type":
+		if !isSolidityKeyword(p.tok(), "is") {
+			return p.errorf("wanted keyword 'is' but got %s", p.got())
+		}
+
+		p.next()
+
+		if d.underlying, err = p.parseType(); err != nil {
+			return err
+		}
+
+		if err := p.expect(TokenSemicolon, ";"); err != nil {
+			return err
+		}
+	}
+
+	return p.declare(&d)
+}
+
+func (p *solidityParser) parseEvent() (*solidityItem, error) {
+	p.next()
+
+	name, err := p.expectName("event name")
+
+	if err != nil {
+		return nil, err
+	}
+
+	inputs, err := p.parseParams()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var item = solidityItem{kind: "event", name: name, inputs: inputs}
+
+	if isAnonymousToken(p.tok()) {
+		item.anonymous = true
+		p.next()
+	}
+
+	return &item, p.expect(TokenSemicolon, ";")
+}
+
+func (p *solidityParser) parseError() (*solidityItem, error) {
+	p.next()
+
+	name, err := p.expectName("error name")
+
+	if err != nil {
+		return nil, err
+	}
+
+	inputs, err := p.parseParams()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &solidityItem{kind: "error", name: name, inputs: inputs}, p.expect(TokenSemicolon, ";")
+}
+
+// parseFunction parses function, constructor, fallback and receive definitions, and returns their visibility
+func (p *solidityParser) parseFunction() (*solidityItem, string, error) {
+	var item = solidityItem{kind: p.tok().ValueString(), mutability: "nonpayable"}
+	p.next()
+
+	if item.kind == "function" {
+		// unnamed functions are fallback functions prior to Solidity 0.6
+		if p.tok().Is(TokenOpenParens) {
+			item.kind = "fallback"
+		} else {
+			name, err := p.expectName("function name")
+
+			if err != nil {
+				return nil, "", err
+			}
+
+			item.name = name
+		}
+	}
+
+	inputs, err := p.parseParams()
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	item.inputs = inputs
+
+	var visibility = "public"
+
+	for {
+		var tok = p.tok()
+
+		switch {
+		case isSolidityKeyword(tok, "external", "public", "internal", "private"):
+			visibility = tok.ValueString()
+			p.next()
+
+		case isStateMutabilityToken(tok):
+			item.mutability = tok.ValueString()
+			p.next()
+
+		// the constant modifier predates view
+		case isSolidityKeyword(tok, "constant"):
+			item.mutability = "view"
+			p.next()
+
+		case isReturnsToken(tok):
+			p.next()
+
+			if item.outputs, err = p.parseParams(); err != nil {
+				return nil, "", err
+			}
+
+		case tok.Is(TokenSemicolon):
+			p.next()
+			return &item, visibility, nil
+
+		case tok.Is(TokenOpenBrace):
+			return &item, visibility, p.skipGroup()
+
+		// virtual, override, override(A, B) and modifier invocations
+		case tok.IsKeyword():
+			if _, err := p.parseQualifiedName(); err != nil {
+				return nil, "", err
+			}
+
+			if p.tok().Is(TokenOpenParens) {
+				if err := p.skipGroup(); err != nil {
+					return nil, "", err
+				}
+			}
+
+		default:
+			return nil, "", p.errorf("wanted function modifier, ';' or '{' but got %s", p.got())
+		}
+	}
+}
+
+// isFunctionTypeVariable tells a state variable of function type, e.g. "function (uint) external returns (uint) cb;",
+// from an unnamed legacy fallback function such as "function () external payable;": the variable has a name and no body
+func (p *solidityParser) isFunctionTypeVariable() bool {
+	var (
+		start = p.tok().ID()
+		named = false
+	)
+
+	defer p.stream.GoTo(start)
+
+	p.next()
+
+	if !p.tok().Is(TokenOpenParens) {
+		return false
+	}
+
+	for {
+		var tok = p.tok()
+
+		switch {
+		case !tok.IsValid() || tok.Is(TokenOpenBrace):
+			return false
+
+		case tok.Is(TokenSemicolon):
+			return named
+
+		case tok.Is(TokenOpenParens, TokenOpenBracket):
+			if p.skipGroup() != nil {
+				return false
+			}
+
+		case tok.IsKeyword() && !isSolidityKeyword(tok, "external", "public", "internal", "private", "pure", "view", "payable", "constant", "returns"):
+			named = true
+			p.next()
+
+		default:
+			p.next()
+		}
+	}
+}
+
+// parseStateVariable returns a getter item for public state variables, nil otherwise
+func (p *solidityParser) parseStateVariable() (*solidityItem, error) {
+	t, err := p.parseType()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var public = false
+
+	for {
+		var tok = p.tok()
+
+		if isSolidityKeyword(tok, "public") {
+			public = true
+			p.next()
+		} else if isSolidityKeyword(tok, "private", "internal", "constant", "immutable", "transient") {
+			p.next()
+		} else if isSolidityKeyword(tok, "override") {
+			p.next()
+
+			if p.tok().Is(TokenOpenParens) {
+				if err := p.skipGroup(); err != nil {
+					return nil, err
+				}
+			}
+		} else {
+			break
+		}
+	}
+
+	name, err := p.expectName("state variable name")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.skipStatement(); err != nil {
+		return nil, err
+	}
+
+	if !public {
+		return nil, nil
+	}
+
+	return &solidityItem{kind: "getter", name: name, mutability: "view", getter: t}, nil
+}
+
+func (p *solidityParser) parseParams() ([]*solidityParam, error) {
+	var res []*solidityParam
+
+	if err := p.expect(TokenOpenParens, "("); err != nil {
+		return nil, err
+	}
+
+	if p.tok().Is(TokenCloseParens) {
+		p.next()
+		return res, nil
+	}
+
+	for {
+		t, err := p.parseType()
+
+		if err != nil {
+			return nil, err
+		}
+
+		var param = solidityParam{typ: t}
+
+		for isDataLocationToken(p.tok()) || isIndexedToken(p.tok()) {
+			param.indexed = param.indexed || isIndexedToken(p.tok())
+			p.next()
+		}
+
+		if p.tok().IsKeyword() {
+			param.name = p.tok().ValueString()
+			p.next()
+		}
+
+		res = append(res, &param)
+
+		switch {
+		case p.tok().Is(TokenComma):
+			p.next()
+		case p.tok().Is(TokenCloseParens):
+			p.next()
+			return res, nil
+		default:
+			return nil, p.errorf("wanted token ',' or ')' but got %s", p.got())
+		}
+	}
+}
+
+func (p *solidityParser) parseType() (*solidityType, error) {
+	var (
+		tok = p.tok()
+		res = solidityType{offset: tok.Offset(), token: tokenString(tok)}
+	)
+
+	switch {
+	case isSolidityKeyword(tok, "mapping"):
+		p.next()
+
+		if err := p.expect(TokenOpenParens, "("); err != nil {
+			return nil, err
+		}
+
+		key, err := p.parseMappingType()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect(TokenArrow, "=>"); err != nil {
+			return nil, err
+		}
+
+		value, err := p.parseMappingType()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect(TokenCloseParens, ")"); err != nil {
+			return nil, err
+		}
+
+		res.key, res.value = key, value
+
+	case isFunctionToken(tok):
+		fn, err := p.parseFunctionType()
+
+		if err != nil {
+			return nil, err
+		}
+
+		res.name, res.fn = "function", fn
+
+	case tok.IsKeyword():
+		name, err := p.parseQualifiedName()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if name == "address" && isPayableToken(p.tok()) {
+			name = "address payable"
+			p.next()
+		}
+
+		res.name = name
+
+	default:
+		return nil, p.errorf("wanted type name but got %s", p.got())
+	}
+
+	for p.tok().Is(TokenOpenBracket) {
+		s, err := p.parseArraySuffix()
+
+		if err != nil {
+			return nil, err
+		}
+
+		res.arrays = res.arrays + s
+	}
+
+	return &res, nil
+}
+
+// parseFunctionType parses a function type, e.g. "function (uint256) external view returns (bool)"
+func (p *solidityParser) parseFunctionType() (*solidityFunctionType, error) {
+	var (
+		res = solidityFunctionType{mutability: "nonpayable"}
+		err error
+	)
+
+	p.next()
+
+	if res.inputs, err = p.parseParams(); err != nil {
+		return nil, err
+	}
+
+	for {
+		var tok = p.tok()
+
+		switch {
+		case isSolidityKeyword(tok, "internal", "external"):
+			res.external = tok.ValueString() == "external"
+			p.next()
+
+		case isStateMutabilityToken(tok):
+			res.mutability = tok.ValueString()
+			p.next()
+
+		case isReturnsToken(tok):
+			p.next()
+
+			if res.outputs, err = p.parseParams(); err != nil {
+				return nil, err
+			}
+
+		default:
+			return &res, nil
+		}
+	}
+}
+
+// parseArraySuffix parses an array suffix; lengths other than integer literals, such as constants, are kept as
+// written, e.g. [N], and cannot be resolved to an ABI type
+func (p *solidityParser) parseArraySuffix() (string, error) {
+	var start = p.tok().ID()
+
+	if s, err := parseArraySuffix(p.stream); err == nil {
+		return s, nil
+	}
+
+	p.stream.GoTo(start)
+	p.next()
+
+	var sb strings.Builder
+
+	for depth := 1; ; {
+		var tok = p.tok()
+
+		switch {
+		case !tok.IsValid():
+			return "", p.errorf("wanted token ']' but got EOF")
+		case tok.Is(TokenOpenBracket):
+			depth++
+		case tok.Is(TokenCloseBracket):
+			depth--
+		}
+
+		p.next()
+
+		if depth == 0 {
+			return "[" + sb.String() + "]", nil
+		}
+
+		sb.WriteString(tokenString(tok))
+	}
+}
+
+// parseMappingType parses a mapping key or value type, with its optional name
+func (p *solidityParser) parseMappingType() (*solidityType, error) {
+	t, err := p.parseType()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tok().IsKeyword() {
+		p.next()
+	}
+
+	return t, nil
+}
+
+func (p *solidityParser) parseQualifiedName() (string, error) {
+	name, err := p.expectName("identifier")
+
+	if err != nil {
+		return "", err
+	}
+
+	for p.tok().Is(TokenDot) {
+		p.next()
+
+		s, err := p.expectName("identifier")
+
+		if err != nil {
+			return "", err
+		}
+
+		name = name + "." + s
+	}
+
+	return name, nil
+}
+
+// skipGroup skips a balanced group of parens, brackets or braces, starting at its opening token
+func (p *solidityParser) skipGroup() error {
+	var depth = 0
+
+	for {
+		var tok = p.tok()
+
+		switch {
+		case !tok.IsValid():
+			return p.errorf("unexpected EOF: unbalanced parens, brackets or braces")
+		case tok.Is(TokenOpenParens, TokenOpenBracket, TokenOpenBrace):
+			depth++
+		case tok.Is(TokenCloseParens, TokenCloseBracket, TokenCloseBrace):
+			depth--
+		}
+
+		p.next()
+
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// skipStatement skips tokens up to a ';' or a brace block, whichever ends the statement
+func (p *solidityParser) skipStatement() error {
+	for {
+		var tok = p.tok()
+
+		switch {
+		case !tok.IsValid():
+			return p.errorf("wanted token ';' but got EOF")
+		case tok.Is(TokenSemicolon):
+			p.next()
+			return nil
+		case tok.Is(TokenOpenBrace):
+			return p.skipGroup()
+		case tok.Is(TokenOpenParens, TokenOpenBracket):
+			if err := p.skipGroup(); err != nil {
+				return err
+			}
+		case tok.Is(TokenCloseParens, TokenCloseBracket, TokenCloseBrace):
+			return p.errorf("unexpected token %s", p.got())
+		default:
+			p.next()
+		}
+	}
+}
+
+// skipDirective skips pragma, import and using directives, which may hold braces, up to their ';'
+func (p *solidityParser) skipDirective() error {
+	for !p.tok().Is(TokenSemicolon) {
+		var tok = p.tok()
+
+		switch {
+		case !tok.IsValid():
+			return p.errorf("wanted token ';' but got EOF")
+		case tok.Is(TokenOpenBrace):
+			if err := p.skipGroup(); err != nil {
+				return err
+			}
+		default:
+			p.next()
+		}
+	}
+
+	p.next()
+	return nil
+}
+
+// resolveContract builds the JSON ABI of a contract, inherited items first; items overridden by the contract are
+// replaced in place. Items that cannot be resolved are skipped, and their errors returned.
+func (p *solidityParser) resolveContract(c *solidityContract) ([]*abi.FieldMarshaling, []error) {
+	var (
+		res     []*abi.FieldMarshaling
+		skipped []error
+		index   = make(map[string]int)
+		seen    = make(map[string]bool)
+	)
+
+	var visit func(c *solidityContract)
+
+	visit = func(c *solidityContract) {
+		if seen[c.name] {
+			return
+		}
+
+		seen[c.name] = true
+
+		// bases missing from the source, e.g. imported ones, are ignored
+		for _, base := range c.bases {
+			if b, ok := p.contracts[base]; ok {
+				visit(b)
+			}
+		}
+
+		for _, item := range c.items {
+			field, err := p.resolveItem(c.name, item)
+
+			if err != nil {
+				skipped = append(skipped, fmt.Errorf("%s %s: %w", c.kind, c.name, err))
+				continue
+			}
+
+			var key = fieldKey(field)
+
+			if i, ok := index[key]; ok {
+				res[i] = field
+				continue
+			}
+
+			index[key] = len(res)
+			res = append(res, field)
+		}
+	}
+
+	visit(c)
+	return res, skipped
+}
+
+// fieldKey identifies the ABI entries a derived contract may redeclare
+func fieldKey(f *abi.FieldMarshaling) string {
+	var types = lo.Map(f.Inputs, func(arg *abi.ArgumentMarshaling, _ int) string { return arg.Type + "/" + arg.InternalType })
+
+	switch f.Type {
+	case "constructor", "fallback", "receive":
+		return f.Type
+	default:
+		return f.Type + " " + f.Name + "(" + strings.Join(types, ",") + ")"
+	}
+}
+
+func (p *solidityParser) resolveItem(scope string, item *solidityItem) (*abi.FieldMarshaling, error) {
+	var (
+		res = abi.FieldMarshaling{Type: item.kind, Name: item.name, Anonymous: item.anonymous}
+		err error
+	)
+
+	switch item.kind {
+	case "function", "constructor", "fallback", "receive":
+		res.StateMutability = item.mutability
+
+		if item.kind == "receive" {
+			res.StateMutability = "payable"
+		}
+
+		if item.kind == "fallback" || item.kind == "receive" {
+			return &res, nil
+		}
+
+	case "getter":
+		res.Type = "function"
+		res.StateMutability = item.mutability
+
+		if err := p.resolveGetter(scope, item.getter, &res); err != nil {
+			return nil, fmt.Errorf("%s: %w", item.name, err)
+		}
+
+		return &res, nil
+	}
+
+	if res.Inputs, err = p.resolveParams(scope, item.inputs); err != nil {
+		return nil, fmt.Errorf("%s %s: %w", item.kind, item.name, err)
+	}
+
+	if res.Outputs, err = p.resolveParams(scope, item.outputs); err != nil {
+		return nil, fmt.Errorf("%s %s: %w", item.kind, item.name, err)
+	}
+
+	return &res, nil
+}
+
+// resolveGetter builds the getter of a public state variable: mapping keys and array indices are its inputs,
+// and struct members other than mappings and arrays are returned as separate outputs
+func (p *solidityParser) resolveGetter(scope string, t *solidityType, res *abi.FieldMarshaling) error {
+	for {
+		if len(t.arrays) > 0 {
+			for range strings.Count(t.arrays, "[") {
+				res.Inputs = append(res.Inputs, &abi.ArgumentMarshaling{Type: "uint256", InternalType: "uint256"})
+			}
+
+			var elem = *t
+			elem.arrays = ""
+			t = &elem
+			continue
+		}
+
+		if t.key != nil {
+			key, err := p.resolveType(scope, t.key, nil)
+
+			if err != nil {
+				return err
+			}
+
+			res.Inputs = append(res.Inputs, key)
+			t = t.value
+			continue
+		}
+
+		break
+	}
+
+	if d := p.lookup(scope, t.name); d != nil && d.kind == "struct" {
+		for _, m := range d.members {
+			if m.typ.key != nil || len(m.typ.arrays) > 0 {
+				continue
+			}
+
+			arg, err := p.resolveType(d.scope, m.typ, nil)
+
+			if err != nil {
+				return err
+			}
+
+			arg.Name = m.name
+			res.Outputs = append(res.Outputs, arg)
+		}
+
+		return nil
+	}
+
+	out, err := p.resolveType(scope, t, nil)
+
+	if err != nil {
+		return err
+	}
+
+	res.Outputs = append(res.Outputs, out)
+	return nil
+}
+
+func (p *solidityParser) resolveParams(scope string, params []*solidityParam) ([]*abi.ArgumentMarshaling, error) {
+	var res []*abi.ArgumentMarshaling
+
+	for _, param := range params {
+		arg, err := p.resolveType(scope, param.typ, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		arg.Name = param.name
+		arg.Indexed = param.indexed
+		res = append(res, arg)
+	}
+
+	return res, nil
+}
+
+// resolveType maps a Solidity type to its ABI type; visiting holds the structs being resolved, to detect recursion
+func (p *solidityParser) resolveType(scope string, t *solidityType, visiting map[string]bool) (*abi.ArgumentMarshaling, error) {
+	if t.key != nil {
+		return nil, p.typeErrorf(t, "mapping types are not allowed in the ABI")
+	}
+
+	if !arraySuffixesRegex.MatchString(t.arrays) {
+		return nil, p.typeErrorf(t, "array length of %s%s is not an integer literal", t.name, t.arrays)
+	}
+
+	if t.fn != nil {
+		return p.resolveFunctionType(scope, t)
+	}
+
+	if typ, internalType, ok := elementaryTypeName(t.name); ok {
+		return &abi.ArgumentMarshaling{Type: typ + t.arrays, InternalType: internalType + t.arrays}, nil
+	}
+
+	var d = p.lookup(scope, t.name)
+
+	if d == nil {
+		return nil, p.typeErrorf(t, "unknown type %s", t.name)
+	}
+
+	switch d.kind {
+	case "struct":
+		if visiting[d.name] {
+			return nil, p.typeErrorf(t, "recursive struct %s", d.name)
+		}
+
+		visiting = lo.Assign(visiting, map[string]bool{d.name: true})
+
+		var res = abi.ArgumentMarshaling{Type: "tuple" + t.arrays, InternalType: "struct " + d.name + t.arrays}
+
+		for _, m := range d.members {
+			c, err := p.resolveType(d.scope, m.typ, visiting)
+
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", d.name, m.name, err)
+			}
+
+			c.Name = m.name
+			res.Components = append(res.Components, c)
+		}
+
+		return &res, nil
+
+	case "enum":
+		return &abi.ArgumentMarshaling{Type: "uint8" + t.arrays, InternalType: "enum " + d.name + t.arrays}, nil
+
+	case "type":
+		u, err := p.resolveType(d.scope, d.underlying, visiting)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", d.name, err)
+		}
+
+		return &abi.ArgumentMarshaling{Type: u.Type + t.arrays, InternalType: d.name + t.arrays}, nil
+
+	default:
+		return &abi.ArgumentMarshaling{Type: "address" + t.arrays, InternalType: "contract " + d.name + t.arrays}, nil
+	}
+}
+
+// resolveFunctionType maps an external function type to the function ABI type, with the internal type solc gives it,
+// e.g. "function (uint256) view external returns (bool)"
+func (p *solidityParser) resolveFunctionType(scope string, t *solidityType) (*abi.ArgumentMarshaling, error) {
+	if !t.fn.external {
+		return nil, p.typeErrorf(t, "internal function types are not allowed in the ABI")
+	}
+
+	inputs, err := p.resolveParams(scope, t.fn.inputs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	outputs, err := p.resolveParams(scope, t.fn.outputs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		internalType = lo.Map(inputs, func(arg *abi.ArgumentMarshaling, _ int) string { return arg.InternalType })
+		sb           strings.Builder
+	)
+
+	sb.WriteString("function (" + strings.Join(internalType, ",") + ")")
+
+	if t.fn.mutability != "nonpayable" {
+		sb.WriteString(" " + t.fn.mutability)
+	}
+
+	sb.WriteString(" external")
+
+	if len(outputs) > 0 {
+		internalType = lo.Map(outputs, func(arg *abi.ArgumentMarshaling, _ int) string { return arg.InternalType })
+		sb.WriteString(" returns (" + strings.Join(internalType, ",") + ")")
+	}
+
+	return &abi.ArgumentMarshaling{Type: "function" + t.arrays, InternalType: sb.String() + t.arrays}, nil
+}
+
+// lookup finds a user-defined type by name from a scope: the contract itself, then its bases, then the file level
+func (p *solidityParser) lookup(scope string, name string) *solidityDecl {
+	var seen = make(map[string]bool)
+
+	var visit func(scope string) *solidityDecl
+
+	visit = func(scope string) *solidityDecl {
+		if seen[scope] {
+			return nil
+		}
+
+		seen[scope] = true
+
+		if d, ok := p.decls[scope+"."+name]; ok {
+			return d
+		}
+
+		if c, ok := p.contracts[scope]; ok {
+			for _, base := range c.bases {
+				if d := visit(base); d != nil {
+					return d
+				}
+			}
+		}
+
+		return nil
+	}
+
+	if len(scope) > 0 {
+		if d := visit(scope); d != nil {
+			return d
+		}
+	}
+
+	return p.decls[name]
+}
+
+// elementaryTypeName returns the canonical ABI type and the internal type of an elementary Solidity type
+func elementaryTypeName(name string) (string, string, bool) {
+	if alias, ok := TYPE_ALIASES[name]; ok {
+		return alias, alias, true
+	}
+
+	switch name {
+	case "fixed", "ufixed":
+		return name + "128x18", name + "128x18", true
+	case "address payable":
+		return "address", name, true
+	}
+
+	if lo.Contains(SCALAR_TYPENAMES, name) || FIXED_TYPENAME_REGEX.MatchString(name) {
+		return name, name, true
+	}
+
+	return "", "", false
+}
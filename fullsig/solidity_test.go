@@ -0,0 +1,346 @@
+package fullsig
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/agnosticeng/evmabi/abi"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/swaggest/assertjson"
+)
+
+const soliditySource = `
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.24;
+
+import {SafeCast} from "./SafeCast.sol";
+
+type Currency is address;
+
+/* file-level struct, { braces } in comments are ignored */
+struct Position {
+    int24 tickLower;
+    int24 tickUpper;
+}
+
+interface IHooks {}
+
+interface IPoolManager {
+    struct Key {
+        Currency currency0;
+        Currency currency1;
+        uint24 fee;
+        int24 tickSpacing;
+        IHooks hooks;
+    }
+
+    struct SwapParams {
+        bool zeroForOne;
+        int256 amountSpecified;
+        uint160 sqrtPriceLimitX96;
+    }
+
+    enum Status { Open, Closed }
+
+    event Swap(bytes32 indexed id, address indexed sender, int128 amount0, int128 amount1);
+
+    error PoolNotInitialized();
+
+    function swap(Key memory key, SwapParams calldata params, bytes calldata hookData) external returns (int256 delta);
+    function status(Key[] calldata keys) external view returns (Status[] memory);
+    function positions(bytes32 id) external view returns (Position memory);
+}
+
+abstract contract Owned {
+    address payable public owner;
+
+    modifier onlyOwner() {
+        require(msg.sender == owner, "not owner }");
+        _;
+    }
+
+    function transferOwnership(address payable newOwner) public virtual onlyOwner {
+        owner = newOwner;
+    }
+}
+
+contract Pool is IPoolManager, Owned {
+    using SafeCast for uint256;
+
+    uint256 internal constant MAX_FEE = 1e6;
+    mapping(bytes32 id => mapping(address => Key)) public pools;
+    uint[2][] public fees;
+
+    constructor(address payable _owner) payable {
+        owner = _owner;
+    }
+
+    receive() external payable {}
+
+    function swap(Key memory key, SwapParams calldata params, bytes calldata) external override returns (int256 delta) {
+        if (key.fee > MAX_FEE) {
+            revert PoolNotInitialized();
+        }
+    }
+
+    function _fee(Key memory key) internal pure returns (uint24) {
+        return key.fee;
+    }
+}
+`
+
+func TestParseSolidity(t *testing.T) {
+	contracts, err := ParseSolidity(soliditySource)
+	assert.NoError(t, err)
+
+	assert.Equal(t,
+		[]string{"interface IHooks", "interface IPoolManager", "contract Owned", "contract Pool"},
+		lo.Map(contracts, func(c *SolidityContract, _ int) string { return c.Kind + " " + c.Name }),
+	)
+	assert.True(t, contracts[2].Abstract)
+
+	var manager = contracts[1]
+
+	assert.Equal(t, "swap((address,address,uint24,int24,address),(bool,int256,uint160),bytes)", manager.ABI.Methods["swap"].Sig)
+	assert.Equal(t, "status((address,address,uint24,int24,address)[])", manager.ABI.Methods["status"].Sig)
+	assert.Equal(t, "Swap(bytes32,address,int128,int128)", manager.ABI.Events["Swap"].Sig)
+	assert.True(t, manager.ABI.Events["Swap"].Inputs[1].Indexed)
+	assert.Contains(t, manager.ABI.Errors, "PoolNotInitialized")
+
+	js, err := json.Marshal(manager.Fields[3])
+	assert.NoError(t, err)
+	assertjson.Equal(t, []byte(`{
+		"type": "function",
+		"name": "status",
+		"stateMutability": "view",
+		"inputs": [{
+			"name": "keys",
+			"type": "tuple[]",
+			"internalType": "struct IPoolManager.Key[]",
+			"components": [
+				{"name": "currency0", "type": "address", "internalType": "Currency"},
+				{"name": "currency1", "type": "address", "internalType": "Currency"},
+				{"name": "fee", "type": "uint24", "internalType": "uint24"},
+				{"name": "tickSpacing", "type": "int24", "internalType": "int24"},
+				{"name": "hooks", "type": "address", "internalType": "contract IHooks"}
+			]
+		}],
+		"outputs": [{"type": "uint8[]", "internalType": "enum IPoolManager.Status[]"}]
+	}`), js)
+
+	assert.Equal(t, "struct Position", manager.Fields[4].Outputs[0].InternalType)
+
+	var pool = contracts[3]
+	var names = lo.Keys(pool.ABI.Methods)
+	sort.Strings(names)
+
+	assert.Equal(t, []string{"fees", "owner", "pools", "positions", "status", "swap", "transferOwnership"}, names)
+	assert.Equal(t, "pools(bytes32,address)", pool.ABI.Methods["pools"].Sig)
+	assert.Len(t, pool.ABI.Methods["pools"].Outputs, 5)
+	assert.Equal(t, "fees(uint256,uint256)", pool.ABI.Methods["fees"].Sig)
+	assert.Equal(t, "view", pool.ABI.Methods["owner"].StateMutability)
+	assert.Equal(t, "address payable", pool.Fields[5].Outputs[0].InternalType)
+	assert.Equal(t, "payable", pool.ABI.Constructor.StateMutability)
+	assert.True(t, pool.ABI.HasReceive())
+	assert.Contains(t, pool.ABI.Events, "Swap")
+
+	// inherited items come first, and the override replaces the inherited declaration in place
+	assert.Equal(t, "swap", pool.Fields[2].Name)
+	assert.Empty(t, pool.Fields[2].Inputs[2].Name)
+}
+
+func TestParseSolidityErrors(t *testing.T) {
+	var tests = []struct {
+		src string
+		err string
+	}{
+		{"interface I {\n  function f(uint256 a b) external;\n}", "line 2, column 24: wanted token ',' or ')' but got b"},
+		{"interface I {\n  event E(uint256);", "line 2, column 20: unexpected EOF in interface I"},
+	}
+
+	for _, test := range tests {
+		_, err := ParseSolidity(test.src)
+		assert.ErrorContains(t, err, test.err, test.src)
+	}
+}
+
+func TestParseSoliditySkipped(t *testing.T) {
+	contracts, err := ParseSolidity(`
+import {IERC20} from "./IERC20.sol";
+
+contract C {
+    uint256 constant N = 3;
+
+    struct S { S[] children; }
+
+    mapping(address => uint) m;
+    uint[N] public xs;
+
+    function f(IERC20 token) external;
+    function g(S memory s) external;
+    function h() external returns (mapping(address => uint) storage);
+    function k(uint[N] memory a) external;
+    function l(function (uint) internal cb) external;
+    function ok(uint256 a) external;
+}`)
+	assert.NoError(t, err)
+	assert.Len(t, contracts, 1)
+
+	var c = contracts[0]
+
+	assert.Equal(t, []string{"xs", "ok"}, lo.Map(c.Fields, func(f *abi.FieldMarshaling, _ int) string { return f.Name }))
+	assert.Equal(t, "xs(uint256)", c.ABI.Methods["xs"].Sig)
+
+	var errs = lo.Map(c.Skipped, func(err error, _ int) string { return err.Error() })
+
+	assert.Equal(t, []string{
+		"contract C: function f: line 12, column 16: unknown type IERC20",
+		"contract C: function g: C.S.children: line 7, column 16: recursive struct C.S",
+		"contract C: function h: line 14, column 36: mapping types are not allowed in the ABI",
+		"contract C: function k: line 15, column 16: array length of uint[N] is not an integer literal",
+		"contract C: function l: line 16, column 16: internal function types are not allowed in the ABI",
+	}, errs)
+
+	var pe *ParseError
+
+	assert.ErrorAs(t, c.Skipped[0], &pe)
+	assert.Equal(t, "    function f(IERC20 token) external;\n               ^^^^^^", pe.Caret())
+}
+
+func TestParseSolidityFunctionTypes(t *testing.T) {
+	contracts, err := ParseSolidity(`
+contract C {
+    function(uint) external returns (uint) public cb;
+    function () external view returns (bool)[] callbacks;
+
+    function() external payable {}
+
+    function call(function (address, uint256) external payable returns (bool) f, uint256 amount) external {}
+    function schedule(function () internal task) internal {}
+}`)
+	assert.NoError(t, err)
+	assert.Len(t, contracts, 1)
+
+	var c = contracts[0]
+
+	assert.Empty(t, c.Skipped)
+	assert.True(t, c.ABI.HasFallback())
+	assert.Equal(t, "payable", c.ABI.Fallback.StateMutability)
+	assert.Equal(t, "cb()", c.ABI.Methods["cb"].Sig)
+	assert.Equal(t, "function", c.ABI.Methods["cb"].Outputs[0].Type.String())
+	assert.Equal(t, "call(function,uint256)", c.ABI.Methods["call"].Sig)
+
+	js, err := json.Marshal(c.Fields[0])
+	assert.NoError(t, err)
+	assertjson.Equal(t, []byte(`{
+		"type": "function",
+		"name": "cb",
+		"stateMutability": "view",
+		"outputs": [{"type": "function", "internalType": "function (uint256) external returns (uint256)"}]
+	}`), js)
+
+	assert.Equal(t, "function (address,uint256) payable external returns (bool)", c.Fields[2].Inputs[0].InternalType)
+}
+
+func TestStringifySolidity(t *testing.T) {
+	contracts, err := ParseSolidity(soliditySource)
+	assert.NoError(t, err)
+
+	src, err := StringifySolidity("IPoolManager", contracts[1].Fields)
+	assert.NoError(t, err)
+	assert.Equal(t, `// SPDX-License-Identifier: UNLICENSED
+pragma solidity ^0.8.4;
+
+interface IPoolManager {
+    struct Key {
+        address currency0;
+        address currency1;
+        uint24 fee;
+        int24 tickSpacing;
+        address hooks;
+    }
+
+    struct SwapParams {
+        bool zeroForOne;
+        int256 amountSpecified;
+        uint160 sqrtPriceLimitX96;
+    }
+
+    struct Position {
+        int24 tickLower;
+        int24 tickUpper;
+    }
+
+    event Swap(bytes32 indexed id, address indexed sender, int128 amount0, int128 amount1);
+
+    error PoolNotInitialized();
+
+    function swap(Key calldata key, SwapParams calldata params, bytes calldata hookData) external returns (int256 delta);
+    function status(Key[] calldata keys) external view returns (uint8[] memory);
+    function positions(bytes32 id) external view returns (Position memory);
+}
+`, src)
+
+	// both renderings parse back to the same selectors and topics
+	for _, c := range contracts {
+		for _, render := range []func() (string, error){
+			func() (string, error) { return StringifySolidity(c.Name, c.Fields) },
+			func() (string, error) { return StringifySolidityABI(c.Name, &c.ABI) },
+		} {
+			src, err := render()
+			assert.NoError(t, err)
+
+			parsed, err := ParseSolidity(src)
+			assert.NoError(t, err, src)
+			assert.Len(t, parsed, 1)
+
+			for name, m := range c.ABI.Methods {
+				assert.Equal(t, m.Sig, parsed[0].ABI.Methods[name].Sig, src)
+				assert.Equal(t, m.StateMutability, parsed[0].ABI.Methods[name].StateMutability, src)
+			}
+
+			for name, e := range c.ABI.Events {
+				assert.Equal(t, e.Sig, parsed[0].ABI.Events[name].Sig, src)
+			}
+
+			assert.Equal(t, len(c.ABI.Errors), len(parsed[0].ABI.Errors))
+			assert.Equal(t, c.ABI.HasReceive(), parsed[0].ABI.HasReceive())
+		}
+	}
+}
+
+func TestStringifySolidityFunctionTypes(t *testing.T) {
+	contracts, err := ParseSolidity(`
+interface I {
+    function call(function (address, uint256) external payable returns (bool) f, uint256 amount) external;
+    function hooks() external view returns (function () external[] memory);
+}`)
+	assert.NoError(t, err)
+
+	src, err := StringifySolidity("I", contracts[0].Fields)
+	assert.NoError(t, err)
+	assert.Contains(t, src, "function call(function (address,uint256) payable external returns (bool) f, uint256 amount) external;")
+
+	parsed, err := ParseSolidity(src)
+	assert.NoError(t, err, src)
+
+	for name, m := range contracts[0].ABI.Methods {
+		assert.Equal(t, m.Sig, parsed[0].ABI.Methods[name].Sig, src)
+	}
+
+	assert.Equal(t, "function[]", parsed[0].ABI.Methods["hooks"].Outputs[0].Type.String())
+	assert.Equal(t, "function () external[]", parsed[0].Fields[1].Outputs[0].InternalType)
+
+	// user-defined types are not declared when only referred to by function types
+	contracts, err = ParseSolidity(`
+interface I {
+    struct S { uint256 a; }
+    function call(function (S memory) external f) external;
+}`)
+	assert.NoError(t, err)
+
+	_, err = StringifySolidity("I", contracts[0].Fields)
+	assert.ErrorContains(t, err, "refers to user-defined types")
+}
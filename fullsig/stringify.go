@@ -7,76 +7,143 @@ import (
 	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
 )
 
-func StringifyEvent(evt *eth_abi.Event) string {
+type StringifyOptions struct {
+	// Names emits parameter names, in the ethers.js human-readable ABI format:
+	// "event Transfer(address indexed from, address indexed to, uint256 value)"
+	Names bool
+}
+
+type StringifyOption func(*StringifyOptions)
+
+func WithNames(names bool) StringifyOption {
+	return func(opts *StringifyOptions) {
+		opts.Names = names
+	}
+}
+
+func NewStringifyOptions(opts ...StringifyOption) StringifyOptions {
+	var res StringifyOptions
+
+	for _, opt := range opts {
+		opt(&res)
+	}
+
+	return res
+}
+
+func StringifyEvent(evt *eth_abi.Event, opts ...StringifyOption) string {
 	var sb strings.Builder
 
 	sb.WriteString("event ")
 	sb.WriteString(evt.RawName)
-	sb.WriteString("(")
-
-	for i, input := range evt.Inputs {
-		sb.WriteString(StringifyArgument(&input))
+	sb.WriteString(stringifyArguments(evt.Inputs, opts...))
 
-		if i != (len(evt.Inputs) - 1) {
-			sb.WriteString(",")
-		}
+	if evt.Anonymous {
+		sb.WriteString(" anonymous")
 	}
 
-	sb.WriteString(")")
 	return sb.String()
 }
 
-func StringifyMethod(meth *eth_abi.Method) string {
+func StringifyError(e *eth_abi.Error, opts ...StringifyOption) string {
+	return "error " + e.Name + stringifyArguments(e.Inputs, opts...)
+}
+
+func StringifyConstructor(ctor *eth_abi.Method, opts ...StringifyOption) string {
 	var sb strings.Builder
 
-	sb.WriteString("function ")
-	sb.WriteString(meth.RawName)
-	sb.WriteString("(")
+	sb.WriteString("constructor")
+	sb.WriteString(stringifyArguments(ctor.Inputs, opts...))
+
+	if ctor.IsPayable() {
+		sb.WriteString(" payable")
+	}
+
+	return sb.String()
+}
 
-	for i, input := range meth.Inputs {
-		sb.WriteString(StringifyArgument(&input))
+// StringifyFragment stringifies the fragment set in f
+func StringifyFragment(f Fragment, opts ...StringifyOption) string {
+	switch {
+	case f.Event != nil:
+		return StringifyEvent(f.Event, opts...)
+	case f.Error != nil:
+		return StringifyError(f.Error, opts...)
+	case f.Method != nil:
+		return StringifyMethod(f.Method, opts...)
+	default:
+		return ""
+	}
+}
 
-		if i != (len(meth.Inputs) - 1) {
-			sb.WriteString(",")
+// StringifyMethod stringifies functions, and also constructors, fallback and receive functions according to the method type
+func StringifyMethod(meth *eth_abi.Method, opts ...StringifyOption) string {
+	switch meth.Type {
+	case eth_abi.Constructor:
+		return StringifyConstructor(meth, opts...)
+
+	case eth_abi.Fallback:
+		if meth.IsPayable() {
+			return "fallback() external payable"
 		}
+
+		return "fallback() external"
+
+	case eth_abi.Receive:
+		return "receive() external payable"
 	}
 
-	sb.WriteString(")")
+	var (
+		sb strings.Builder
+		o  = NewStringifyOptions(opts...)
+	)
 
-	if len(meth.Outputs) > 0 {
-		sb.WriteString("(")
+	sb.WriteString("function ")
+	sb.WriteString(meth.RawName)
+	sb.WriteString(stringifyArguments(meth.Inputs, opts...))
 
-		for i, output := range meth.Outputs {
-			sb.WriteString(StringifyArgument(&output))
+	// nonpayable is the default state mutability and has no Solidity keyword
+	var hasMutability = len(meth.StateMutability) > 0 && meth.StateMutability != "nonpayable"
 
-			if i != (len(meth.Outputs) - 1) {
-				sb.WriteString(",")
-			}
+	if hasMutability {
+		sb.WriteString(" ")
+		sb.WriteString(meth.StateMutability)
+	}
+
+	if len(meth.Outputs) > 0 {
+		// the compact form f()(uint256) is kept unless the signature is otherwise Solidity-style
+		if hasMutability || o.Names {
+			sb.WriteString(" returns ")
 		}
 
-		sb.WriteString(")")
+		sb.WriteString(stringifyArguments(meth.Outputs, opts...))
 	}
 
 	return sb.String()
 }
 
-func StringifyArgument(arg *eth_abi.Argument) string {
+func StringifyArgument(arg *eth_abi.Argument, opts ...StringifyOption) string {
 	var sb strings.Builder
 
-	sb.WriteString(StringifyType(&arg.Type))
+	sb.WriteString(StringifyType(&arg.Type, opts...))
 
 	if arg.Indexed {
 		sb.WriteString(" indexed")
 	}
 
+	if NewStringifyOptions(opts...).Names && len(arg.Name) > 0 {
+		sb.WriteString(" ")
+		sb.WriteString(arg.Name)
+	}
+
 	return sb.String()
 }
 
-func StringifyType(t *eth_abi.Type) string {
+func StringifyType(t *eth_abi.Type, opts ...StringifyOption) string {
 	switch t.T {
 	case eth_abi.SliceTy, eth_abi.ArrayTy:
 		var sb strings.Builder
-		sb.WriteString(StringifyType(t.Elem))
+		sb.WriteString(StringifyType(t.Elem, opts...))
 		sb.WriteString("[")
 
 		if t.Size > 0 {
@@ -88,21 +155,48 @@ func StringifyType(t *eth_abi.Type) string {
 		return sb.String()
 
 	case eth_abi.TupleTy:
-		var sb strings.Builder
-		sb.WriteString("(")
+		var (
+			sb   strings.Builder
+			o    = NewStringifyOptions(opts...)
+			args = make(eth_abi.Arguments, len(t.TupleElems))
+		)
 
 		for i := 0; i < len(t.TupleRawNames); i++ {
-			sb.WriteString(StringifyType(t.TupleElems[i]))
+			args[i] = eth_abi.Argument{Name: t.TupleRawNames[i], Type: *t.TupleElems[i]}
+		}
 
-			if i < len(t.TupleRawNames)-1 {
-				sb.WriteString(",")
-			}
+		if o.Names {
+			sb.WriteString("tuple")
 		}
 
-		sb.WriteString(")")
+		sb.WriteString(stringifyArguments(args, opts...))
 		return sb.String()
 
 	default:
 		return t.String()
 	}
 }
+
+func stringifyArguments(args eth_abi.Arguments, opts ...StringifyOption) string {
+	var (
+		sb  strings.Builder
+		sep = ","
+	)
+
+	if NewStringifyOptions(opts...).Names {
+		sep = ", "
+	}
+
+	sb.WriteString("(")
+
+	for i, arg := range args {
+		sb.WriteString(StringifyArgument(&arg, opts...))
+
+		if i != (len(args) - 1) {
+			sb.WriteString(sep)
+		}
+	}
+
+	sb.WriteString(")")
+	return sb.String()
+}
@@ -0,0 +1,347 @@
+package fullsig
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/agnosticeng/evmabi/abi"
+	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/samber/lo"
+)
+
+var userDefinedTypeRegex = regexp.MustCompile(`\b(struct|enum|contract)\s`)
+
+// StringifySolidity renders a JSON ABI as a Solidity interface. Structs are reconstructed from tuples and named after
+// their internalType ("struct Pool.Key" gives Key), enums and contract types are rendered as their ABI types, and
+// reference types get the calldata location in function inputs and memory in outputs.
+// Constructors are skipped since interfaces cannot declare them.
+func StringifySolidity(name string, fields []*abi.FieldMarshaling) (string, error) {
+	var (
+		w                       = solidityWriter{structs: make(map[string]string), taken: make(map[string]bool)}
+		events, errs, functions []string
+		fallback, receive       string
+	)
+
+	for _, f := range fields {
+		switch f.Type {
+		case "event":
+			params, err := w.params(f.Inputs, "")
+
+			if err != nil {
+				return "", fmt.Errorf("event %s: %w", f.Name, err)
+			}
+
+			var s = "event " + f.Name + "(" + params + ")"
+
+			if f.Anonymous {
+				s = s + " anonymous"
+			}
+
+			events = append(events, s+";")
+
+		case "error":
+			params, err := w.params(f.Inputs, "")
+
+			if err != nil {
+				return "", fmt.Errorf("error %s: %w", f.Name, err)
+			}
+
+			errs = append(errs, "error "+f.Name+"("+params+");")
+
+		case "function", "":
+			s, err := w.function(f)
+
+			if err != nil {
+				return "", fmt.Errorf("function %s: %w", f.Name, err)
+			}
+
+			functions = append(functions, s)
+
+		case "fallback":
+			fallback = "fallback() external;"
+
+			if fieldMutability(f) == "payable" {
+				fallback = "fallback() external payable;"
+			}
+
+		case "receive":
+			receive = "receive() external payable;"
+
+		case "constructor":
+			continue
+
+		default:
+			return "", fmt.Errorf("wrong field type: %s", f.Type)
+		}
+	}
+
+	if len(fallback) > 0 {
+		functions = append(functions, fallback)
+	}
+
+	if len(receive) > 0 {
+		functions = append(functions, receive)
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("// SPDX-License-Identifier: UNLICENSED\n")
+	sb.WriteString("pragma solidity ^0.8.4;\n\n")
+	sb.WriteString("interface " + name + " {\n")
+
+	var sections = lo.Compact([]string{
+		strings.Join(w.defs, "\n\n"),
+		strings.Join(events, "\n"),
+		strings.Join(errs, "\n"),
+		strings.Join(functions, "\n"),
+	})
+
+	if len(sections) > 0 {
+		for _, line := range strings.Split(strings.Join(sections, "\n\n"), "\n") {
+			if len(line) > 0 {
+				sb.WriteString("    " + line)
+			}
+
+			sb.WriteString("\n")
+		}
+	}
+
+	sb.WriteString("}\n")
+	return sb.String(), nil
+}
+
+// StringifySolidityABI renders an ABI as a Solidity interface, see StringifySolidity. Fragments are sorted by
+// signature since the ABI does not keep their order; struct names come from the tuple raw names, e.g. PoolKey.
+func StringifySolidityABI(name string, a *eth_abi.ABI) (string, error) {
+	var fields []*abi.FieldMarshaling
+
+	for _, evt := range sortedBySig(lo.Values(a.Events), func(e eth_abi.Event) string { return e.Sig }) {
+		f, err := abi.EventToFieldMarshaling(&evt)
+
+		if err != nil {
+			return "", err
+		}
+
+		fields = append(fields, f)
+	}
+
+	for _, e := range sortedBySig(lo.Values(a.Errors), func(e eth_abi.Error) string { return e.Sig }) {
+		f, err := abi.ErrorToFieldMarshaling(&e)
+
+		if err != nil {
+			return "", err
+		}
+
+		fields = append(fields, f)
+	}
+
+	var methods = sortedBySig(lo.Values(a.Methods), func(m eth_abi.Method) string { return m.Sig })
+
+	if a.HasFallback() {
+		methods = append(methods, a.Fallback)
+	}
+
+	if a.HasReceive() {
+		methods = append(methods, a.Receive)
+	}
+
+	for _, m := range methods {
+		f, err := abi.MethodToFieldMarshaling(&m)
+
+		if err != nil {
+			return "", err
+		}
+
+		fields = append(fields, f)
+	}
+
+	return StringifySolidity(name, fields)
+}
+
+func sortedBySig[T any](items []T, sig func(T) string) []T {
+	slices.SortFunc(items, func(a T, b T) int { return strings.Compare(sig(a), sig(b)) })
+	return items
+}
+
+type solidityWriter struct {
+	// structs maps struct definitions to their name; defs holds them in order, nested structs first
+	structs map[string]string
+	taken   map[string]bool
+	defs    []string
+}
+
+func (w *solidityWriter) function(f *abi.FieldMarshaling) (string, error) {
+	inputs, err := w.params(f.Inputs, "calldata")
+
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("function " + f.Name + "(" + inputs + ") external")
+
+	if mutability := fieldMutability(f); mutability != "nonpayable" {
+		sb.WriteString(" " + mutability)
+	}
+
+	if len(f.Outputs) > 0 {
+		outputs, err := w.params(f.Outputs, "memory")
+
+		if err != nil {
+			return "", err
+		}
+
+		sb.WriteString(" returns (" + outputs + ")")
+	}
+
+	sb.WriteString(";")
+	return sb.String(), nil
+}
+
+// fieldMutability falls back to the constant and payable flags of ABIs predating stateMutability
+func fieldMutability(f *abi.FieldMarshaling) string {
+	switch {
+	case len(f.StateMutability) > 0:
+		return f.StateMutability
+	case f.Payable:
+		return "payable"
+	case f.Constant:
+		return "view"
+	default:
+		return "nonpayable"
+	}
+}
+
+// params renders a parameter list; location is set on reference types if not empty
+func (w *solidityWriter) params(args []*abi.ArgumentMarshaling, location string) (string, error) {
+	var res []string
+
+	for _, arg := range args {
+		t, err := w.typeName(arg)
+
+		if err != nil {
+			return "", err
+		}
+
+		var parts = []string{t}
+
+		if arg.Indexed {
+			parts = append(parts, "indexed")
+		}
+
+		if len(location) > 0 && isReferenceType(arg.Type) {
+			parts = append(parts, location)
+		}
+
+		if len(arg.Name) > 0 {
+			parts = append(parts, arg.Name)
+		}
+
+		res = append(res, strings.Join(parts, " "))
+	}
+
+	return strings.Join(res, ", "), nil
+}
+
+func (w *solidityWriter) typeName(arg *abi.ArgumentMarshaling) (string, error) {
+	switch {
+	case strings.HasPrefix(arg.Type, "tuple"):
+		name, err := w.structName(arg)
+
+		if err != nil {
+			return "", err
+		}
+
+		return name + strings.TrimPrefix(arg.Type, "tuple"), nil
+
+	// function types can only be rendered from their internal type, e.g. "function (uint256) external returns (bool)",
+	// which cannot refer to user-defined types since only the structs of tuples are declared
+	case strings.HasPrefix(arg.Type, "function"):
+		if !strings.HasPrefix(arg.InternalType, "function ") {
+			return "", fmt.Errorf("function type %s has no internal type", arg.Name)
+		}
+
+		if userDefinedTypeRegex.MatchString(arg.InternalType) {
+			return "", fmt.Errorf("function type %s refers to user-defined types: %s", arg.Name, arg.InternalType)
+		}
+
+		return arg.InternalType, nil
+
+	case strings.HasPrefix(arg.InternalType, "address payable"):
+		return arg.InternalType, nil
+
+	default:
+		return arg.Type, nil
+	}
+}
+
+// structName returns the name of the struct defining a tuple, and defines it on first use. Distinct tuples mapping
+// to the same name are numbered, e.g. Key and Key1.
+func (w *solidityWriter) structName(arg *abi.ArgumentMarshaling) (string, error) {
+	var members []string
+
+	for i, c := range arg.Components {
+		t, err := w.typeName(c)
+
+		if err != nil {
+			return "", err
+		}
+
+		var name = c.Name
+
+		if len(name) == 0 {
+			name = "field" + strconv.Itoa(i)
+		}
+
+		members = append(members, t+" "+name+";")
+	}
+
+	var (
+		base = structBaseName(arg.InternalType)
+		key  = base + "{" + strings.Join(members, " ") + "}"
+	)
+
+	if name, ok := w.structs[key]; ok {
+		return name, nil
+	}
+
+	var name = base
+
+	for i := 1; w.taken[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+
+	w.taken[name] = true
+	w.structs[key] = name
+	w.defs = append(w.defs, "struct "+name+" {\n    "+strings.Join(members, "\n    ")+"\n}")
+	return name, nil
+}
+
+// structBaseName extracts the struct name from an internal type such as "struct Pool.Key[]" or a raw tuple name
+func structBaseName(internalType string) string {
+	var s = internalType
+
+	if i := strings.Index(s, "["); i != -1 {
+		s = s[:i]
+	}
+
+	s = strings.TrimPrefix(s, "struct ")
+
+	if i := strings.LastIndex(s, "."); i != -1 {
+		s = s[i+1:]
+	}
+
+	if len(s) == 0 || s == "tuple" || strings.Contains(s, " ") {
+		return "Struct"
+	}
+
+	return s
+}
+
+func isReferenceType(t string) bool {
+	return t == "bytes" || t == "string" || strings.HasSuffix(t, "]") || strings.HasPrefix(t, "tuple")
+}
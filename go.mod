@@ -3,7 +3,6 @@ module github.com/agnosticeng/evmabi
 go 1.23.1
 
 require (
-	github.com/agnosticeng/panicsafe v0.5.0
 	github.com/bytedance/sonic v1.12.2
 	github.com/bzick/tokenizer v1.4.7
 	github.com/ethereum/go-ethereum v1.14.8
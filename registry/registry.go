@@ -0,0 +1,223 @@
+package registry
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/agnosticeng/evmabi/abi"
+	"github.com/agnosticeng/evmabi/encoding"
+	"github.com/agnosticeng/evmabi/encoding/json"
+	"github.com/agnosticeng/evmabi/fullsig"
+	"github.com/bytedance/sonic/ast"
+	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var ErrNotFound = errors.New("not found")
+
+// Registry indexes ABI fragments by selector and topic0. Colliding fragments are all kept, in insertion order,
+// and are tried in turn when decoding: use encoding.WithStrict(true) to better discriminate between them.
+// A Registry is safe for concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	methods map[[4]byte][]eth_abi.Method
+	events  map[common.Hash][]eth_abi.Event
+	errors  map[[4]byte][]eth_abi.Error
+}
+
+func New() *Registry {
+	return &Registry{
+		methods: make(map[[4]byte][]eth_abi.Method),
+		events:  make(map[common.Hash][]eth_abi.Event),
+		errors:  make(map[[4]byte][]eth_abi.Error),
+	}
+}
+
+func (r *Registry) AddABI(a eth_abi.ABI) {
+	for _, m := range a.Methods {
+		r.AddMethod(m)
+	}
+
+	for _, e := range a.Events {
+		r.AddEvent(e)
+	}
+
+	for _, e := range a.Errors {
+		r.AddError(e)
+	}
+}
+
+func (r *Registry) AddArtifacts(artifacts []*abi.Artifact) {
+	for _, artifact := range artifacts {
+		r.AddABI(artifact.ABI)
+	}
+}
+
+func (r *Registry) AddSignatures(sigs *fullsig.Signatures) {
+	for _, m := range sigs.Methods {
+		r.AddMethod(m)
+	}
+
+	for _, e := range sigs.Events {
+		r.AddEvent(e)
+	}
+
+	for _, e := range sigs.Errors {
+		r.AddError(e)
+	}
+}
+
+// AddMethod indexes a function by selector; constructors, fallback and receive functions have none and are skipped
+func (r *Registry) AddMethod(m eth_abi.Method) {
+	if m.Type != eth_abi.Function {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var (
+		selector   = [4]byte(m.ID)
+		candidates = r.methods[selector]
+	)
+
+	if slices.ContainsFunc(candidates, func(c eth_abi.Method) bool { return c.Sig == m.Sig }) {
+		return
+	}
+
+	r.methods[selector] = append(candidates, m)
+}
+
+// AddEvent indexes an event by topic0; events that only differ by argument indexing are distinct candidates.
+// Anonymous events have no topic0 to be looked up by and are skipped.
+func (r *Registry) AddEvent(e eth_abi.Event) {
+	if e.Anonymous {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var (
+		sig        = fullsig.StringifyEvent(&e)
+		candidates = r.events[e.ID]
+	)
+
+	if slices.ContainsFunc(candidates, func(c eth_abi.Event) bool { return fullsig.StringifyEvent(&c) == sig }) {
+		return
+	}
+
+	r.events[e.ID] = append(candidates, e)
+}
+
+func (r *Registry) AddError(e eth_abi.Error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var (
+		selector   = [4]byte(e.ID[:4])
+		candidates = r.errors[selector]
+	)
+
+	if slices.ContainsFunc(candidates, func(c eth_abi.Error) bool { return c.Sig == e.Sig }) {
+		return
+	}
+
+	r.errors[selector] = append(candidates, e)
+}
+
+func (r *Registry) Methods(selector [4]byte) []eth_abi.Method {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.methods[selector])
+}
+
+func (r *Registry) Events(topic0 common.Hash) []eth_abi.Event {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.events[topic0])
+}
+
+func (r *Registry) Errors(selector [4]byte) []eth_abi.Error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.errors[selector])
+}
+
+func (r *Registry) DecodeCallData(data []byte, opts ...encoding.DecodeOption) (ast.Node, error) {
+	if len(data) < 4 {
+		return ast.Node{}, fmt.Errorf("call data is smaller than 4 bytes")
+	}
+
+	var (
+		candidates = r.Methods([4]byte(data[:4]))
+		errs       []error
+	)
+
+	if len(candidates) == 0 {
+		return ast.Node{}, fmt.Errorf("unknown selector %s: %w", hexutil.Encode(data[:4]), ErrNotFound)
+	}
+
+	for _, m := range candidates {
+		node, err := json.DecodeCallData(data, m, opts...)
+
+		if err == nil {
+			return node, nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", m.Sig, err))
+	}
+
+	return ast.Node{}, fmt.Errorf("no candidate decoded call data: %w", errors.Join(errs...))
+}
+
+func (r *Registry) DecodeLog(topics [][32]byte, data []byte, opts ...encoding.DecodeOption) (ast.Node, error) {
+	if len(topics) == 0 {
+		return ast.Node{}, fmt.Errorf("log has no topics")
+	}
+
+	var candidates = r.Events(topics[0])
+
+	if len(candidates) == 0 {
+		return ast.Node{}, fmt.Errorf("unknown topic0 %s: %w", common.Hash(topics[0]).Hex(), ErrNotFound)
+	}
+
+	node, _, err := json.DecodeLogVariants(topics, data, candidates, opts...)
+	return node, err
+}
+
+// DecodeRevert decodes revert data with the registered errors, or with the builtin Error(string) and Panic(uint256)
+func (r *Registry) DecodeRevert(data []byte, opts ...encoding.DecodeOption) (ast.Node, error) {
+	if len(data) < 4 {
+		return ast.Node{}, fmt.Errorf("revert data is smaller than 4 bytes")
+	}
+
+	var (
+		candidates = r.Errors([4]byte(data[:4]))
+		errs       []error
+	)
+
+	if len(candidates) == 0 {
+		if !bytes.Equal(data[:4], json.BuiltinError.ID[:4]) && !bytes.Equal(data[:4], json.BuiltinPanic.ID[:4]) {
+			return ast.Node{}, fmt.Errorf("unknown error selector %s: %w", hexutil.Encode(data[:4]), ErrNotFound)
+		}
+
+		return json.DecodeRevert(data, nil, opts...)
+	}
+
+	for _, e := range candidates {
+		node, err := json.DecodeRevert(data, []eth_abi.Error{e}, opts...)
+
+		if err == nil {
+			return node, nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", e.Sig, err))
+	}
+
+	return ast.Node{}, fmt.Errorf("no candidate decoded revert data: %w", errors.Join(errs...))
+}
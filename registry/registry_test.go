@@ -0,0 +1,147 @@
+package registry
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/agnosticeng/evmabi/encoding"
+	"github.com/agnosticeng/evmabi/fullsig"
+	eth_abi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/swaggest/assertjson"
+)
+
+func TestDecodeCallDataCollision(t *testing.T) {
+	var (
+		r       = New()
+		collate = lo.Must(fullsig.ParseMethod("function collate_propagate_storage(bytes16)"))
+		burn    = lo.Must(fullsig.ParseMethod("function burn(uint256)"))
+		data    = append(burn.ID[:4:4], common.BigToHash(big.NewInt(1)).Bytes()...)
+	)
+
+	r.AddMethod(collate)
+	r.AddMethod(burn)
+	r.AddMethod(burn)
+
+	assert.Equal(t, collate.ID, burn.ID)
+	assert.Len(t, r.Methods([4]byte(burn.ID)), 2)
+
+	node, err := r.DecodeCallData(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "collate_propagate_storage(bytes16)", lo.Must(node.Get("signature").String()))
+
+	node, err = r.DecodeCallData(data, encoding.WithStrict(true))
+	assert.NoError(t, err)
+	assert.Equal(t, "burn(uint256)", lo.Must(node.Get("signature").String()))
+
+	_, err = r.DecodeCallData([]byte{1, 2, 3, 4})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestDecodeLog(t *testing.T) {
+	var (
+		r      = New()
+		erc20  = lo.Must(fullsig.ParseEvent("event Transfer(address indexed,address indexed,uint256)"))
+		erc721 = lo.Must(fullsig.ParseEvent("event Transfer(address indexed,address indexed,uint256 indexed)"))
+		value  = common.BigToHash(big.NewInt(42))
+	)
+
+	r.AddEvent(erc20)
+	r.AddEvent(erc721)
+	assert.Len(t, r.Events(erc20.ID), 2)
+
+	node, err := r.DecodeLog([][32]byte{erc721.ID, {}, {}, value}, nil)
+	assert.NoError(t, err)
+	js, err := node.MarshalJSON()
+	assert.NoError(t, err)
+	assertjson.Equal(t, []byte(`{
+		"signature": "Transfer(address,address,uint256)",
+		"inputs": {
+			"arg0": "0x0000000000000000000000000000000000000000",
+			"arg1": "0x0000000000000000000000000000000000000000",
+			"arg2": "42"
+		}
+	}`), js)
+
+	_, err = r.DecodeLog([][32]byte{{}}, nil)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestDecodeRevert(t *testing.T) {
+	var (
+		r    = New()
+		e    = lo.Must(fullsig.ParseMethod("function InsufficientBalance(uint256)"))
+		data = append(e.ID[:4:4], common.BigToHash(big.NewInt(1)).Bytes()...)
+	)
+
+	_, err := r.DecodeRevert(data)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	r.AddError(eth_abi.NewError("InsufficientBalance", e.Inputs))
+
+	node, err := r.DecodeRevert(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "InsufficientBalance(uint256)", lo.Must(node.Get("signature").String()))
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	var (
+		r    = New()
+		burn = lo.Must(fullsig.ParseMethod("function burn(uint256)"))
+		data = append(burn.ID[:4:4], common.BigToHash(big.NewInt(1)).Bytes()...)
+		wg   sync.WaitGroup
+	)
+
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			r.AddMethod(burn)
+		}()
+
+		go func() {
+			defer wg.Done()
+			_, _ = r.DecodeCallData(data)
+		}()
+	}
+
+	wg.Wait()
+	assert.Len(t, r.Methods([4]byte(burn.ID)), 1)
+}
+
+func TestAddMethodWithoutSelector(t *testing.T) {
+	var r = New()
+
+	for _, sig := range []string{"constructor(uint256 supply) payable", "receive() external payable", "fallback() external"} {
+		f, err := fullsig.ParseFragment(sig)
+		assert.NoError(t, err)
+		assert.NotPanics(t, func() { r.AddMethod(*f.Method) }, sig)
+	}
+
+	var a = eth_abi.ABI{
+		Constructor: lo.Must(fullsig.ParseConstructor("constructor(address owner)")),
+		Receive:     lo.Must(fullsig.ParseReceive("receive() external payable")),
+		Methods:     map[string]eth_abi.Method{"burn": lo.Must(fullsig.ParseMethod("function burn(uint256)"))},
+	}
+
+	assert.NotPanics(t, func() { r.AddABI(a) })
+	assert.Len(t, r.Methods([4]byte(a.Methods["burn"].ID)), 1)
+	assert.Empty(t, r.Methods([4]byte{}))
+}
+
+func TestAddAnonymousEvent(t *testing.T) {
+	var (
+		r         = New()
+		anonymous = lo.Must(fullsig.ParseEvent("event Transfer(address indexed,address indexed,uint256) anonymous"))
+	)
+
+	r.AddEvent(anonymous)
+	r.AddSignatures(&fullsig.Signatures{Events: []eth_abi.Event{anonymous}})
+	r.AddABI(eth_abi.ABI{Events: map[string]eth_abi.Event{"Transfer": anonymous}})
+
+	assert.Empty(t, r.Events(anonymous.ID))
+}